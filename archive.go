@@ -0,0 +1,143 @@
+package pixiv
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// RankingRecord is a line of a ranking archive.
+type RankingRecord struct {
+	Mode string `json:"mode"`
+	Date string `json:"date"`
+	// Rank is the 1-based position of the illust in the ranking, that is
+	// the offset of its page plus its index in the page plus one.
+	Rank   int    `json:"rank"`
+	Illust Illust `json:"illust"`
+}
+
+// RankingArchiver snapshots rankings into JSON Lines files of
+// RankingRecords named Dir/<mode>/<YYYY-MM-DD>.jsonl.
+//
+// A ranking whose file already exists is skipped, so an interrupted archive
+// resumes where it stopped when run again. Files are written to a .part file
+// first and renamed on completion, so a file that exists is complete.
+type RankingArchiver struct {
+	Client *Client
+	Dir    string
+	Modes  []string
+
+	// OnArchive, if set, is called after every ranking is written or
+	// skipped.
+	OnArchive func(mode string, date time.Time, path string, skipped bool)
+}
+
+// Archive archives the rankings of every mode for every date from from to
+// to, both inclusive.
+func (a *RankingArchiver) Archive(ctx context.Context, from time.Time, to time.Time) error {
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		for _, mode := range a.Modes {
+			if err := a.ArchiveDay(ctx, mode, date); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ArchiveDay archives a single ranking.
+func (a *RankingArchiver) ArchiveDay(ctx context.Context, mode string, date time.Time) error {
+	path := a.Path(mode, date)
+
+	if _, err := os.Stat(path); err == nil {
+		a.onArchive(mode, date, path, true)
+		return nil
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	part := path + ".part"
+
+	f, err := os.Create(part)
+	if err != nil {
+		return err
+	}
+
+	if err := a.write(ctx, f, mode, date); err != nil {
+		f.Close()
+		os.Remove(part)
+		return fmt.Errorf("failed to archive %s ranking of %s: %v", mode, date.Format("2006-01-02"), err)
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(part)
+		return err
+	}
+
+	if err := os.Rename(part, path); err != nil {
+		return err
+	}
+
+	a.onArchive(mode, date, path, false)
+	return nil
+}
+
+// Path returns the path of the archive of a ranking.
+func (a *RankingArchiver) Path(mode string, date time.Time) string {
+	return filepath.Join(a.Dir, mode, date.Format("2006-01-02")+".jsonl")
+}
+
+func (a *RankingArchiver) write(ctx context.Context, f *os.File, mode string, date time.Time) error {
+	params := NewGetIllustRankingParams().SetMode(mode).SetDate(date.Format("2006-01-02"))
+
+	pager := a.Client.NewPager(func(ctx context.Context) (Page, error) {
+		return a.Client.GetIllustRanking(ctx, params)
+	})
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+
+	offset := 0
+	for pager.Next(ctx) {
+		illusts := pager.Page().(*GetIllustRanking).Illusts
+
+		for i, illust := range illusts {
+			record := RankingRecord{
+				Mode:   mode,
+				Date:   date.Format("2006-01-02"),
+				Rank:   offset + i + 1,
+				Illust: illust,
+			}
+			if err := enc.Encode(record); err != nil {
+				return err
+			}
+		}
+
+		offset += len(illusts)
+	}
+
+	if err := pager.Err(); err != nil {
+		return err
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	return f.Sync()
+}
+
+func (a *RankingArchiver) onArchive(mode string, date time.Time, path string, skipped bool) {
+	if a.OnArchive != nil {
+		a.OnArchive(mode, date, path, skipped)
+	}
+}
@@ -0,0 +1,189 @@
+package pixiv
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func readRankingRecords(t *testing.T, path string) []RankingRecord {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	records := []RankingRecord{}
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		var record RankingRecord
+		if err := json.Unmarshal(s.Bytes(), &record); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return records
+}
+
+func TestRankingArchiver_Archive(t *testing.T) {
+	var (
+		mx       sync.Mutex
+		requests []string
+		failing  = "week 2017-09-02"
+	)
+
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		key := q.Get("mode") + " " + q.Get("date")
+
+		mx.Lock()
+		requests = append(requests, key)
+		fail := key == failing
+		mx.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write(fixture("fixtures/api_error.json"))
+			return
+		}
+
+		offset, _ := strconv.Atoi(q.Get("offset"))
+
+		// Two pages of two illusts each.
+		ranking := GetIllustRanking{}
+		for i := 0; i < 2; i++ {
+			ranking.Illusts = append(ranking.Illusts, Illust{ID: 100 + offset + i, Title: key})
+		}
+		if offset == 0 {
+			q.Set("offset", "2")
+			ranking.NextURL = ts.URL + r.URL.Path + "?" + q.Encode()
+		}
+
+		json.NewEncoder(w).Encode(ranking)
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "pixiv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	skipped := []string{}
+
+	a := &RankingArchiver{
+		Client: &Client{TokenProvider: tp, BaseURL: ts.URL},
+		Dir:    dir,
+		Modes:  []string{RankingModeDay, RankingModeWeek},
+		OnArchive: func(mode string, date time.Time, path string, skip bool) {
+			if skip {
+				skipped = append(skipped, mode+" "+date.Format("2006-01-02"))
+			}
+		},
+	}
+
+	from := time.Date(2017, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2017, 9, 3, 0, 0, 0, 0, time.UTC)
+
+	// An existing archive is never fetched again.
+	existing := a.Path(RankingModeDay, from)
+	if err := os.MkdirAll(filepath.Dir(existing), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(existing, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Archive(context.TODO(), from, to); err == nil {
+		t.Fatal("Archive() should return an error if a ranking cannot be fetched")
+	}
+
+	expectedRequests := []string{
+		"week 2017-09-01", "week 2017-09-01",
+		"day 2017-09-02", "day 2017-09-02",
+		"week 2017-09-02",
+	}
+	if g, e := requests, expectedRequests; !reflect.DeepEqual(g, e) {
+		t.Errorf("got requests %q, want %q", g, e)
+	}
+
+	if _, err := os.Stat(a.Path(RankingModeWeek, from.AddDate(0, 0, 1)) + ".part"); !os.IsNotExist(err) {
+		t.Errorf("the .part file of a failed ranking should be removed")
+	}
+
+	expectedRecords := []RankingRecord{}
+	for i := 0; i < 4; i++ {
+		expectedRecords = append(expectedRecords, RankingRecord{
+			Mode:   "day",
+			Date:   "2017-09-02",
+			Rank:   i + 1,
+			Illust: Illust{ID: 100 + i, Title: "day 2017-09-02"},
+		})
+	}
+	if g, e := readRankingRecords(t, a.Path(RankingModeDay, from.AddDate(0, 0, 1))), expectedRecords; !reflect.DeepEqual(g, e) {
+		t.Errorf("got %#v, want %#v", g, e)
+	}
+
+	// Resume after the failure has gone away.
+	mx.Lock()
+	requests = nil
+	failing = ""
+	mx.Unlock()
+	skipped = nil
+
+	if err := a.Archive(context.TODO(), from, to); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRequests = []string{
+		"week 2017-09-02", "week 2017-09-02",
+		"day 2017-09-03", "day 2017-09-03",
+		"week 2017-09-03", "week 2017-09-03",
+	}
+	if g, e := requests, expectedRequests; !reflect.DeepEqual(g, e) {
+		t.Errorf("got requests %q, want %q", g, e)
+	}
+
+	expectedSkipped := []string{"day 2017-09-01", "week 2017-09-01", "day 2017-09-02"}
+	if g, e := skipped, expectedSkipped; !reflect.DeepEqual(g, e) {
+		t.Errorf("got skipped %q, want %q", g, e)
+	}
+
+	for _, mode := range a.Modes {
+		for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+			path := a.Path(mode, date)
+			if path == existing {
+				continue
+			}
+
+			if g, e := len(readRankingRecords(t, path)), 4; g != e {
+				t.Errorf("%s: got %d records, want %d", path, g, e)
+			}
+		}
+	}
+
+	if g, e := a.Path(RankingModeWeek, to), filepath.Join(dir, "week", "2017-09-03.jsonl"); g != e {
+		t.Errorf("got path %q, want %q", g, e)
+	}
+}
@@ -0,0 +1,86 @@
+package pixiv
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchIllustDetailResult is the outcome of fetching the detail of a single
+// illust in a batch. Exactly one of Detail and Err is set.
+type BatchIllustDetailResult struct {
+	IllustID int
+	Detail   *GetIllustDetail
+	Err      error
+}
+
+// BatchGetIllustDetail fetches the details of ids with up to workers
+// concurrent requests. See BatchGetIllustDetailChan.
+func (c *Client) BatchGetIllustDetail(ctx context.Context, ids []int, workers int) <-chan BatchIllustDetailResult {
+	ch := make(chan int)
+
+	go func() {
+		defer close(ch)
+
+		for _, id := range ids {
+			select {
+			case ch <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return c.BatchGetIllustDetailChan(ctx, ch, workers)
+}
+
+// BatchGetIllustDetailChan fetches the detail of every ID received from ids
+// with up to workers concurrent requests. Results are sent in completion
+// order, and a failure for one ID does not stop the others. The returned
+// channel is closed once ids is closed and drained, or ctx is done.
+func (c *Client) BatchGetIllustDetailChan(ctx context.Context, ids <-chan int, workers int) <-chan BatchIllustDetailResult {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make(chan BatchIllustDetailResult)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+
+			for {
+				var (
+					id int
+					ok bool
+				)
+
+				select {
+				case id, ok = <-ids:
+					if !ok {
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+
+				detail, err := c.GetIllustDetail(ctx, NewGetIllustDetailParams().SetIllustID(id))
+
+				select {
+				case results <- BatchIllustDetailResult{IllustID: id, Detail: detail, Err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
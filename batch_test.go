@@ -0,0 +1,129 @@
+package pixiv
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_BatchGetIllustDetail(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	var (
+		mx          sync.Mutex
+		running     int
+		maxRunning  int
+		requestedID []int
+	)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.URL.Query().Get("illust_id"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mx.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		requestedID = append(requestedID, id)
+		mx.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mx.Lock()
+		running--
+		mx.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if id%3 == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write(fixture("fixtures/api_error.json"))
+			return
+		}
+
+		w.Write([]byte(`{"illust":{"id":` + strconv.Itoa(id) + `}}`))
+	}))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	ids := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	succeeded := []int{}
+	failed := []int{}
+
+	for result := range cli.BatchGetIllustDetail(context.TODO(), ids, 3) {
+		if result.Err != nil {
+			if _, ok := result.Err.(ErrAPI); !ok {
+				t.Errorf("got error %#v, want an ErrAPI", result.Err)
+			}
+			failed = append(failed, result.IllustID)
+			continue
+		}
+
+		if g, e := result.Detail.Illust.ID, result.IllustID; g != e {
+			t.Errorf("got Illust.ID %v, want %v", g, e)
+		}
+		succeeded = append(succeeded, result.IllustID)
+	}
+
+	sort.Ints(succeeded)
+	sort.Ints(failed)
+
+	if g, e := succeeded, []int{1, 2, 4, 5, 7, 8, 10}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got succeeded IDs %v, want %v", g, e)
+	}
+
+	if g, e := failed, []int{3, 6, 9}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got failed IDs %v, want %v", g, e)
+	}
+
+	if maxRunning > 3 {
+		t.Errorf("got %d concurrent requests, want at most %d", maxRunning, 3)
+	}
+}
+
+func TestClient_BatchGetIllustDetailChan_Canceled(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"illust":{"id":1}}`))
+	}))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// ids is never closed; only the cancellation can end the batch.
+	ids := make(chan int)
+
+	results := cli.BatchGetIllustDetailChan(ctx, ids, 2)
+
+	ids <- 1
+
+	if result := <-results; result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-results:
+		if ok {
+			t.Errorf("no more results should be sent after cancellation")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("the result channel should be closed after cancellation")
+	}
+}
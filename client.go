@@ -1,9 +1,11 @@
 package pixiv
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -24,21 +26,75 @@ type Client struct {
 	BaseURL       string
 	Headers       map[string]string
 	TokenProvider TokenProvider
+
+	// RetryPolicy, if set, retries GET and HEAD requests that fail with
+	// a transient error.
+	RetryPolicy *RetryPolicy
+
+	// Limiter, if set, is waited on before every request is sent.
+	Limiter Limiter
 }
 
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
-	token, err := c.TokenProvider.Token(req.Context())
+	if c.RetryPolicy != nil && isIdempotent(req.Method) {
+		return c.RetryPolicy.do(req, c.do)
+	}
+
+	return c.do(req)
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	res, token, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
 
+	invalidator, ok := c.TokenProvider.(TokenInvalidator)
+	if !ok || !isAuthFailure(res) {
+		return res, nil
+	}
+
+	replay, err := rewindRequest(req)
+	if err != nil || replay == nil {
+		return res, nil
+	}
+
+	res.Body.Close()
+
+	invalidator.InvalidateToken(token)
+
+	res, _, err = c.send(replay)
+	return res, err
+}
+
+func (c *Client) send(req *http.Request) (*http.Response, string, error) {
+	if c.Limiter != nil {
+		if err := c.Limiter.Wait(req.Context()); err != nil {
+			return nil, "", err
+		}
+	}
+
+	token, err := c.TokenProvider.Token(req.Context())
+	if err != nil {
+		return nil, "", err
+	}
+
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 
 	for k, v := range c.headers() {
 		req.Header.Set(k, v)
 	}
 
-	return c.client().Do(req)
+	res, err := c.client().Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if limiter, ok := c.Limiter.(AdaptiveLimiter); ok && isRateLimited(res) {
+		limiter.RateLimited()
+	}
+
+	return res, token, nil
 }
 
 func (c *Client) client() *http.Client {
@@ -62,6 +118,54 @@ func (c *Client) headers() map[string]string {
 	return c.Headers
 }
 
+func (c *Client) get(ctx context.Context, url string, val interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return c.onFailure(res)
+	}
+
+	return c.onSuccess(res, val)
+}
+
+// post sends form as a form-encoded POST body and decodes the response into
+// val, which may be nil if the response carries nothing of interest. POST
+// requests are not retried by RetryPolicy, but the body can be replayed
+// once after an auth failure.
+func (c *Client) post(ctx context.Context, url string, form url.Values, val interface{}) error {
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := c.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return c.onFailure(res)
+	}
+
+	if val == nil {
+		return nil
+	}
+
+	return c.onSuccess(res, val)
+}
+
 func (c *Client) onSuccess(res *http.Response, val interface{}) error {
 	if !strings.Contains(res.Header.Get("Content-Type"), "application/json") {
 		return fmt.Errorf("Content-Type header = %q, should be \"application/json\"", res.Header.Get("Content-Type"))
@@ -70,6 +174,73 @@ func (c *Client) onSuccess(res *http.Response, val interface{}) error {
 	return json.NewDecoder(res.Body).Decode(val)
 }
 
+// isAuthFailure reports whether res indicates that the access token was
+// rejected by the OAuth layer. The response body is left unread.
+func isAuthFailure(res *http.Response) bool {
+	if res.StatusCode != http.StatusBadRequest && res.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+
+	body, ok := peekAPIErrorBody(res)
+	if !ok {
+		return false
+	}
+
+	return strings.Contains(body.Error.Message, "invalid_grant") ||
+		strings.Contains(body.Error.Message, "Error occurred at the OAuth process")
+}
+
+// peekAPIErrorBody decodes the body of res as an APIErrorBody and then
+// restores it so that it can be read again.
+func peekAPIErrorBody(res *http.Response) (APIErrorBody, bool) {
+	var body APIErrorBody
+
+	if !strings.Contains(res.Header.Get("Content-Type"), "application/json") {
+		return body, false
+	}
+
+	buf, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = ioutil.NopCloser(bytes.NewReader(buf))
+	if err != nil {
+		return body, false
+	}
+
+	if err := json.Unmarshal(buf, &body); err != nil {
+		return body, false
+	}
+
+	return body, true
+}
+
+// rewindRequest returns a copy of req that can be sent again, or nil if the
+// request body cannot be replayed.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	r := new(http.Request)
+	*r = *req
+
+	r.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		r.Header[k] = append([]string(nil), v...)
+	}
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return r, nil
+	}
+
+	if req.GetBody == nil {
+		return nil, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	r.Body = body
+	return r, nil
+}
+
 func (c *Client) onFailure(res *http.Response) error {
 	errAPI := ErrAPI{StatusCode: res.StatusCode, Status: res.Status}
 
@@ -169,28 +340,9 @@ func (c *Client) GetIllustRanking(ctx context.Context, params *GetIllustRankingP
 		return nil, err
 	}
 
-	req, err := http.NewRequest(
-		http.MethodGet,
-		c.baseURL()+"/v1/illust/ranking?"+params.buildQuery(),
-		nil,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	res, err := c.Do(req.WithContext(ctx))
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode != http.StatusOK {
-		return nil, c.onFailure(res)
-	}
-
 	var result GetIllustRanking
 
-	if err := c.onSuccess(res, &result); err != nil {
+	if err := c.get(ctx, c.baseURL()+"/v1/illust/ranking?"+params.buildQuery(), &result); err != nil {
 		return nil, err
 	}
 
@@ -198,24 +350,9 @@ func (c *Client) GetIllustRanking(ctx context.Context, params *GetIllustRankingP
 }
 
 func (c *Client) GetIllustRankingNext(ctx context.Context, nextURL string) (*GetIllustRanking, error) {
-	req, err := http.NewRequest(http.MethodGet, nextURL, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	res, err := c.Do(req.WithContext(ctx))
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode != http.StatusOK {
-		return nil, c.onFailure(res)
-	}
-
 	var ranking GetIllustRanking
 
-	if err := c.onSuccess(res, &ranking); err != nil {
+	if err := c.get(ctx, nextURL, &ranking); err != nil {
 		return nil, err
 	}
 
@@ -262,28 +399,210 @@ func (c *Client) GetIllustDetail(ctx context.Context, params *GetIllustDetailPar
 		return nil, err
 	}
 
-	req, err := http.NewRequest(
-		http.MethodGet,
-		c.baseURL()+"/v1/illust/detail?"+params.buildQuery(),
-		nil,
-	)
-	if err != nil {
+	var result GetIllustDetail
+
+	if err := c.get(ctx, c.baseURL()+"/v1/illust/detail?"+params.buildQuery(), &result); err != nil {
 		return nil, err
 	}
 
-	res, err := c.Do(req.WithContext(ctx))
-	if err != nil {
+	return &result, nil
+}
+
+const (
+	SearchTargetPartialMatchForTags = "partial_match_for_tags"
+	SearchTargetExactMatchForTags   = "exact_match_for_tags"
+	SearchTargetTitleAndCaption     = "title_and_caption"
+)
+
+const (
+	SearchSortDateDesc    = "date_desc"
+	SearchSortDateAsc     = "date_asc"
+	SearchSortPopularDesc = "popular_desc"
+)
+
+const (
+	SearchDurationWithinLastDay   = "within_last_day"
+	SearchDurationWithinLastWeek  = "within_last_week"
+	SearchDurationWithinLastMonth = "within_last_month"
+)
+
+type SearchIllustParams struct {
+	Word         *string
+	SearchTarget *string
+	Sort         *string
+	StartDate    *string
+	EndDate      *string
+	Duration     *string
+	Offset       *int
+	Filter       *string
+}
+
+func NewSearchIllustParams() *SearchIllustParams {
+	return &SearchIllustParams{}
+}
+
+func (p *SearchIllustParams) SetWord(word string) *SearchIllustParams {
+	p.Word = &word
+	return p
+}
+
+func (p *SearchIllustParams) SetSearchTarget(searchTarget string) *SearchIllustParams {
+	p.SearchTarget = &searchTarget
+	return p
+}
+
+func (p *SearchIllustParams) SetSort(sort string) *SearchIllustParams {
+	p.Sort = &sort
+	return p
+}
+
+func (p *SearchIllustParams) SetStartDate(startDate string) *SearchIllustParams {
+	p.StartDate = &startDate
+	return p
+}
+
+func (p *SearchIllustParams) SetEndDate(endDate string) *SearchIllustParams {
+	p.EndDate = &endDate
+	return p
+}
+
+func (p *SearchIllustParams) SetDuration(duration string) *SearchIllustParams {
+	p.Duration = &duration
+	return p
+}
+
+func (p *SearchIllustParams) SetOffset(offset int) *SearchIllustParams {
+	p.Offset = &offset
+	return p
+}
+
+func (p *SearchIllustParams) SetFilter(filter string) *SearchIllustParams {
+	p.Filter = &filter
+	return p
+}
+
+func (p *SearchIllustParams) Validate() error {
+	err := &ErrInvalidParams{}
+
+	if p.Word == nil {
+		err.Add(ErrInvalidParam{"Word", "missing required field"})
+	} else if *p.Word == "" {
+		err.Add(ErrInvalidParam{"Word", "must not be empty"})
+	}
+
+	if p.SearchTarget == nil {
+		err.Add(ErrInvalidParam{"SearchTarget", "missing required field"})
+	}
+
+	if err.Len() > 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (p *SearchIllustParams) buildQuery() string {
+	v := url.Values{}
+
+	v.Set("word", *p.Word)
+	v.Set("search_target", *p.SearchTarget)
+
+	if p.Sort != nil {
+		v.Set("sort", *p.Sort)
+	}
+
+	if p.StartDate != nil {
+		v.Set("start_date", *p.StartDate)
+	}
+
+	if p.EndDate != nil {
+		v.Set("end_date", *p.EndDate)
+	}
+
+	if p.Duration != nil {
+		v.Set("duration", *p.Duration)
+	}
+
+	if p.Offset != nil {
+		v.Set("offset", strconv.Itoa(*p.Offset))
+	}
+
+	if p.Filter != nil {
+		v.Set("filter", *p.Filter)
+	} else {
+		v.Set("filter", "for_android")
+	}
+
+	return v.Encode()
+}
+
+func (c *Client) SearchIllust(ctx context.Context, params *SearchIllustParams) (*SearchIllust, error) {
+	if err := params.Validate(); err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	if res.StatusCode != http.StatusOK {
-		return nil, c.onFailure(res)
+	var result SearchIllust
+
+	if err := c.get(ctx, c.baseURL()+"/v1/search/illust?"+params.buildQuery(), &result); err != nil {
+		return nil, err
 	}
 
-	var result GetIllustDetail
+	return &result, nil
+}
+
+func (c *Client) SearchIllustNext(ctx context.Context, nextURL string) (*SearchIllust, error) {
+	var result SearchIllust
+
+	if err := c.get(ctx, nextURL, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+type GetUgoiraMetadataParams struct {
+	IllustID *int
+}
+
+func NewGetUgoiraMetadataParams() *GetUgoiraMetadataParams {
+	return &GetUgoiraMetadataParams{}
+}
+
+func (p *GetUgoiraMetadataParams) SetIllustID(illustID int) *GetUgoiraMetadataParams {
+	p.IllustID = &illustID
+	return p
+}
+
+func (p *GetUgoiraMetadataParams) Validate() error {
+	err := &ErrInvalidParams{}
+
+	if p.IllustID == nil {
+		err.Add(ErrInvalidParam{"IllustID", "missing required field"})
+	}
+
+	if err.Len() > 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (p *GetUgoiraMetadataParams) buildQuery() string {
+	v := url.Values{}
+
+	v.Set("illust_id", strconv.Itoa(*p.IllustID))
+
+	return v.Encode()
+}
+
+func (c *Client) GetUgoiraMetadata(ctx context.Context, params *GetUgoiraMetadataParams) (*GetUgoiraMetadata, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result GetUgoiraMetadata
 
-	if err := c.onSuccess(res, &result); err != nil {
+	if err := c.get(ctx, c.baseURL()+"/v1/ugoira/metadata?"+params.buildQuery(), &result); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,177 @@
+package pixiv
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+type AddIllustBookmarkParams struct {
+	IllustID *int
+	Restrict *string
+	Tags     []string
+}
+
+func NewAddIllustBookmarkParams() *AddIllustBookmarkParams {
+	return &AddIllustBookmarkParams{}
+}
+
+func (p *AddIllustBookmarkParams) SetIllustID(illustID int) *AddIllustBookmarkParams {
+	p.IllustID = &illustID
+	return p
+}
+
+func (p *AddIllustBookmarkParams) SetRestrict(restrict string) *AddIllustBookmarkParams {
+	p.Restrict = &restrict
+	return p
+}
+
+func (p *AddIllustBookmarkParams) SetTags(tags []string) *AddIllustBookmarkParams {
+	p.Tags = tags
+	return p
+}
+
+func (p *AddIllustBookmarkParams) Validate() error {
+	err := &ErrInvalidParams{}
+
+	if p.IllustID == nil {
+		err.Add(ErrInvalidParam{"IllustID", "missing required field"})
+	}
+
+	if p.Restrict != nil && *p.Restrict != RestrictPublic && *p.Restrict != RestrictPrivate {
+		err.Add(ErrInvalidParam{"Restrict", "must be either \"public\" or \"private\""})
+	}
+
+	for _, tag := range p.Tags {
+		if tag == "" {
+			err.Add(ErrInvalidParam{"Tags", "must not contain an empty tag"})
+			break
+		}
+	}
+
+	if err.Len() > 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (p *AddIllustBookmarkParams) buildForm() url.Values {
+	v := url.Values{}
+
+	v.Set("illust_id", strconv.Itoa(*p.IllustID))
+
+	if p.Restrict != nil {
+		v.Set("restrict", *p.Restrict)
+	} else {
+		v.Set("restrict", RestrictPublic)
+	}
+
+	for _, tag := range p.Tags {
+		v.Add("tags[]", tag)
+	}
+
+	return v
+}
+
+// AddIllustBookmark bookmarks an illust, or updates the restrict and tags of
+// an existing bookmark.
+func (c *Client) AddIllustBookmark(ctx context.Context, params *AddIllustBookmarkParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	return c.post(ctx, c.baseURL()+"/v2/illust/bookmark/add", params.buildForm(), nil)
+}
+
+type DeleteIllustBookmarkParams struct {
+	IllustID *int
+}
+
+func NewDeleteIllustBookmarkParams() *DeleteIllustBookmarkParams {
+	return &DeleteIllustBookmarkParams{}
+}
+
+func (p *DeleteIllustBookmarkParams) SetIllustID(illustID int) *DeleteIllustBookmarkParams {
+	p.IllustID = &illustID
+	return p
+}
+
+func (p *DeleteIllustBookmarkParams) Validate() error {
+	err := &ErrInvalidParams{}
+
+	if p.IllustID == nil {
+		err.Add(ErrInvalidParam{"IllustID", "missing required field"})
+	}
+
+	if err.Len() > 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (p *DeleteIllustBookmarkParams) buildForm() url.Values {
+	v := url.Values{}
+
+	v.Set("illust_id", strconv.Itoa(*p.IllustID))
+
+	return v
+}
+
+func (c *Client) DeleteIllustBookmark(ctx context.Context, params *DeleteIllustBookmarkParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	return c.post(ctx, c.baseURL()+"/v1/illust/bookmark/delete", params.buildForm(), nil)
+}
+
+type GetIllustBookmarkDetailParams struct {
+	IllustID *int
+}
+
+func NewGetIllustBookmarkDetailParams() *GetIllustBookmarkDetailParams {
+	return &GetIllustBookmarkDetailParams{}
+}
+
+func (p *GetIllustBookmarkDetailParams) SetIllustID(illustID int) *GetIllustBookmarkDetailParams {
+	p.IllustID = &illustID
+	return p
+}
+
+func (p *GetIllustBookmarkDetailParams) Validate() error {
+	err := &ErrInvalidParams{}
+
+	if p.IllustID == nil {
+		err.Add(ErrInvalidParam{"IllustID", "missing required field"})
+	}
+
+	if err.Len() > 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (p *GetIllustBookmarkDetailParams) buildQuery() string {
+	v := url.Values{}
+
+	v.Set("illust_id", strconv.Itoa(*p.IllustID))
+
+	return v.Encode()
+}
+
+func (c *Client) GetIllustBookmarkDetail(ctx context.Context, params *GetIllustBookmarkDetailParams) (*GetIllustBookmarkDetail, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result GetIllustBookmarkDetail
+
+	if err := c.get(ctx, c.baseURL()+"/v2/illust/bookmark/detail?"+params.buildQuery(), &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,18 @@
+package pixiv
+
+type GetIllustBookmarkDetail struct {
+	BookmarkDetail GetIllustBookmarkDetailBookmarkDetail `json:"bookmark_detail"`
+}
+
+type GetIllustBookmarkDetailBookmarkDetail struct {
+	IsBookmarked bool                         `json:"is_bookmarked"`
+	Tags         []GetIllustBookmarkDetailTag `json:"tags"`
+	Restrict     string                       `json:"restrict"`
+}
+
+// GetIllustBookmarkDetailTag is a tag that can be attached to the bookmark.
+// IsRegistered reports whether it is attached.
+type GetIllustBookmarkDetailTag struct {
+	Name         string `json:"name"`
+	IsRegistered bool   `json:"is_registered"`
+}
@@ -0,0 +1,165 @@
+package pixiv
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestClient_AddIllustBookmark(t *testing.T) {
+	cases := []struct {
+		name   string
+		params *AddIllustBookmarkParams
+		form   url.Values
+	}{
+		{
+			name:   "default",
+			params: NewAddIllustBookmarkParams().SetIllustID(64936066),
+			form: url.Values{
+				"illust_id": []string{"64936066"},
+				"restrict":  []string{"public"},
+			},
+		},
+		{
+			name: "private with tags",
+			params: NewAddIllustBookmarkParams().
+				SetIllustID(64936066).
+				SetRestrict(RestrictPrivate).
+				SetTags([]string{"オリジナル", "星空"}),
+			form: url.Values{
+				"illust_id": []string{"64936066"},
+				"restrict":  []string{"private"},
+				"tags[]":    []string{"オリジナル", "星空"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+			ts := newFixtureServer(t, http.MethodPost, "/v2/illust/bookmark/add", c.form, nil)
+			defer ts.Close()
+
+			cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+			if err := cli.AddIllustBookmark(context.TODO(), c.params); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestAddIllustBookmarkParams_Validate(t *testing.T) {
+	cases := []struct {
+		name   string
+		params *AddIllustBookmarkParams
+		fields []string
+	}{
+		{
+			name:   "missing illust ID",
+			params: NewAddIllustBookmarkParams(),
+			fields: []string{"IllustID"},
+		},
+		{
+			name:   "invalid restrict and empty tag",
+			params: NewAddIllustBookmarkParams().SetIllustID(1).SetRestrict("all").SetTags([]string{"a", ""}),
+			fields: []string{"Restrict", "Tags"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err, ok := c.params.Validate().(*ErrInvalidParams)
+			if !ok {
+				t.Fatalf("Validate() should return an *ErrInvalidParams")
+			}
+
+			fields := []string{}
+			for _, e := range err.Errs {
+				fields = append(fields, e.Field)
+			}
+
+			if g, e := fields, c.fields; !reflect.DeepEqual(g, e) {
+				t.Errorf("got invalid fields %q, want %q", g, e)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteIllustBookmark(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := newFixtureServer(t, http.MethodPost, "/v1/illust/bookmark/delete", url.Values{"illust_id": []string{"64936066"}}, nil)
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	if err := cli.DeleteIllustBookmark(context.TODO(), NewDeleteIllustBookmarkParams().SetIllustID(64936066)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := cli.DeleteIllustBookmark(context.TODO(), NewDeleteIllustBookmarkParams()).(*ErrInvalidParams); !ok {
+		t.Errorf("DeleteIllustBookmark() should return an *ErrInvalidParams if IllustID is missing")
+	}
+}
+
+func TestClient_AddIllustBookmark_NotRetried(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cnt++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	cli := &Client{
+		TokenProvider: tp,
+		BaseURL:       ts.URL,
+		RetryPolicy:   &RetryPolicy{Sleep: (&recordingSleeper{}).Sleep},
+	}
+
+	err := cli.AddIllustBookmark(context.TODO(), NewAddIllustBookmarkParams().SetIllustID(1))
+	if _, ok := err.(ErrAPI); !ok {
+		t.Fatalf("AddIllustBookmark() should return an ErrAPI, got %v", err)
+	}
+
+	if g, e := cnt, 1; g != e {
+		t.Errorf("got %d requests, want %d", g, e)
+	}
+}
+
+func TestClient_GetIllustBookmarkDetail(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := newFixtureServer(t, http.MethodGet, "/v2/illust/bookmark/detail", url.Values{
+		"illust_id": []string{"64936066"},
+	}, fixture("fixtures/get_illust_bookmark_detail.json"))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	detail, err := cli.GetIllustBookmarkDetail(context.TODO(), NewGetIllustBookmarkDetailParams().SetIllustID(64936066))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &GetIllustBookmarkDetail{
+		BookmarkDetail: GetIllustBookmarkDetailBookmarkDetail{
+			IsBookmarked: true,
+			Tags: []GetIllustBookmarkDetailTag{
+				{Name: "オリジナル", IsRegistered: true},
+				{Name: "女の子", IsRegistered: false},
+				{Name: "星空", IsRegistered: true},
+			},
+			Restrict: RestrictPrivate,
+		},
+	}
+	if g, e := detail, expected; !reflect.DeepEqual(g, e) {
+		t.Errorf("got %#v, want %#v", g, e)
+	}
+}
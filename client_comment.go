@@ -0,0 +1,291 @@
+package pixiv
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+type GetIllustCommentsParams struct {
+	IllustID             *int
+	Offset               *int
+	IncludeTotalComments *bool
+}
+
+func NewGetIllustCommentsParams() *GetIllustCommentsParams {
+	return &GetIllustCommentsParams{}
+}
+
+func (p *GetIllustCommentsParams) SetIllustID(illustID int) *GetIllustCommentsParams {
+	p.IllustID = &illustID
+	return p
+}
+
+func (p *GetIllustCommentsParams) SetOffset(offset int) *GetIllustCommentsParams {
+	p.Offset = &offset
+	return p
+}
+
+func (p *GetIllustCommentsParams) SetIncludeTotalComments(includeTotalComments bool) *GetIllustCommentsParams {
+	p.IncludeTotalComments = &includeTotalComments
+	return p
+}
+
+func (p *GetIllustCommentsParams) Validate() error {
+	err := &ErrInvalidParams{}
+
+	if p.IllustID == nil {
+		err.Add(ErrInvalidParam{"IllustID", "missing required field"})
+	}
+
+	if err.Len() > 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (p *GetIllustCommentsParams) buildQuery() string {
+	v := url.Values{}
+
+	v.Set("illust_id", strconv.Itoa(*p.IllustID))
+
+	if p.Offset != nil {
+		v.Set("offset", strconv.Itoa(*p.Offset))
+	}
+
+	if p.IncludeTotalComments != nil {
+		v.Set("include_total_comments", strconv.FormatBool(*p.IncludeTotalComments))
+	}
+
+	return v.Encode()
+}
+
+// GetIllustComments returns the top-level comments of an illust, newest
+// first. Replies are fetched with GetIllustCommentReplies.
+func (c *Client) GetIllustComments(ctx context.Context, params *GetIllustCommentsParams) (*GetIllustComments, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result GetIllustComments
+
+	if err := c.get(ctx, c.baseURL()+"/v3/illust/comments?"+params.buildQuery(), &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (c *Client) GetIllustCommentsNext(ctx context.Context, nextURL string) (*GetIllustComments, error) {
+	var result GetIllustComments
+
+	if err := c.get(ctx, nextURL, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+type GetIllustCommentRepliesParams struct {
+	CommentID *int
+}
+
+func NewGetIllustCommentRepliesParams() *GetIllustCommentRepliesParams {
+	return &GetIllustCommentRepliesParams{}
+}
+
+func (p *GetIllustCommentRepliesParams) SetCommentID(commentID int) *GetIllustCommentRepliesParams {
+	p.CommentID = &commentID
+	return p
+}
+
+func (p *GetIllustCommentRepliesParams) Validate() error {
+	err := &ErrInvalidParams{}
+
+	if p.CommentID == nil {
+		err.Add(ErrInvalidParam{"CommentID", "missing required field"})
+	}
+
+	if err.Len() > 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (p *GetIllustCommentRepliesParams) buildQuery() string {
+	v := url.Values{}
+
+	v.Set("comment_id", strconv.Itoa(*p.CommentID))
+
+	return v.Encode()
+}
+
+// GetIllustCommentReplies returns the direct replies to a comment.
+func (c *Client) GetIllustCommentReplies(ctx context.Context, params *GetIllustCommentRepliesParams) (*GetIllustCommentReplies, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result GetIllustCommentReplies
+
+	if err := c.get(ctx, c.baseURL()+"/v2/illust/comment/replies?"+params.buildQuery(), &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (c *Client) GetIllustCommentRepliesNext(ctx context.Context, nextURL string) (*GetIllustCommentReplies, error) {
+	var result GetIllustCommentReplies
+
+	if err := c.get(ctx, nextURL, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+type AddIllustCommentParams struct {
+	IllustID        *int
+	Comment         *string
+	StampID         *int
+	ParentCommentID *int
+}
+
+func NewAddIllustCommentParams() *AddIllustCommentParams {
+	return &AddIllustCommentParams{}
+}
+
+func (p *AddIllustCommentParams) SetIllustID(illustID int) *AddIllustCommentParams {
+	p.IllustID = &illustID
+	return p
+}
+
+func (p *AddIllustCommentParams) SetComment(comment string) *AddIllustCommentParams {
+	p.Comment = &comment
+	return p
+}
+
+// SetStampID posts a stamp instead of a text comment.
+func (p *AddIllustCommentParams) SetStampID(stampID int) *AddIllustCommentParams {
+	p.StampID = &stampID
+	return p
+}
+
+// SetParentCommentID posts the comment as a reply to another comment.
+func (p *AddIllustCommentParams) SetParentCommentID(parentCommentID int) *AddIllustCommentParams {
+	p.ParentCommentID = &parentCommentID
+	return p
+}
+
+func (p *AddIllustCommentParams) Validate() error {
+	err := &ErrInvalidParams{}
+
+	if p.IllustID == nil {
+		err.Add(ErrInvalidParam{"IllustID", "missing required field"})
+	}
+
+	if p.Comment != nil && p.StampID != nil {
+		err.Add(ErrInvalidParam{"Comment", "must not be set along with StampID"})
+	} else if p.Comment == nil && p.StampID == nil {
+		err.Add(ErrInvalidParam{"Comment", "missing required field"})
+	} else if p.Comment != nil && *p.Comment == "" {
+		err.Add(ErrInvalidParam{"Comment", "must not be empty"})
+	}
+
+	if err.Len() > 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (p *AddIllustCommentParams) buildForm() url.Values {
+	v := url.Values{}
+
+	v.Set("illust_id", strconv.Itoa(*p.IllustID))
+
+	if p.Comment != nil {
+		v.Set("comment", *p.Comment)
+	}
+
+	if p.StampID != nil {
+		v.Set("stamp_id", strconv.Itoa(*p.StampID))
+	}
+
+	if p.ParentCommentID != nil {
+		v.Set("parent_comment_id", strconv.Itoa(*p.ParentCommentID))
+	}
+
+	return v
+}
+
+// AddIllustComment posts a comment on an illust and returns the posted
+// comment.
+func (c *Client) AddIllustComment(ctx context.Context, params *AddIllustCommentParams) (*AddIllustComment, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result AddIllustComment
+
+	if err := c.post(ctx, c.baseURL()+"/v1/illust/comment/add", params.buildForm(), &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// WalkCommentFunc is called by WalkIllustComments for every comment.
+// parents holds the comments that comment replies to, the top-level comment
+// first, and is empty for a top-level comment.
+type WalkCommentFunc func(comment Comment, parents []Comment) error
+
+// WalkIllustComments walks the comments of an illust depth-first, fetching
+// every page of comments and replies. Each comment is visited before its
+// replies. If fn returns an error the walk stops and the error is returned.
+func (c *Client) WalkIllustComments(ctx context.Context, illustID int, fn WalkCommentFunc) error {
+	pager := c.NewPager(func(ctx context.Context) (Page, error) {
+		return c.GetIllustComments(ctx, NewGetIllustCommentsParams().SetIllustID(illustID))
+	})
+
+	return c.walkComments(ctx, pager, nil, fn)
+}
+
+func (c *Client) walkComments(ctx context.Context, pager *Pager, parents []Comment, fn WalkCommentFunc) error {
+	for pager.Next(ctx) {
+		var comments []Comment
+
+		switch page := pager.Page().(type) {
+		case *GetIllustComments:
+			comments = page.Comments
+		case *GetIllustCommentReplies:
+			comments = page.Comments
+		}
+
+		for _, comment := range comments {
+			if err := fn(comment, parents); err != nil {
+				return err
+			}
+
+			if !comment.HasReplies {
+				continue
+			}
+
+			commentID := comment.ID
+			replies := c.NewPager(func(ctx context.Context) (Page, error) {
+				return c.GetIllustCommentReplies(ctx, NewGetIllustCommentRepliesParams().SetCommentID(commentID))
+			})
+
+			// The capacity is limited so that the parents of siblings do not
+			// share a backing array.
+			if err := c.walkComments(ctx, replies, append(parents[:len(parents):len(parents)], comment), fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return pager.Err()
+}
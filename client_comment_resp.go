@@ -0,0 +1,40 @@
+package pixiv
+
+import "time"
+
+type GetIllustComments struct {
+	TotalComments        int       `json:"total_comments"`
+	Comments             []Comment `json:"comments"`
+	NextURL              string    `json:"next_url"`
+	CommentAccessControl int       `json:"comment_access_control"`
+}
+
+type GetIllustCommentReplies struct {
+	Comments []Comment `json:"comments"`
+	NextURL  string    `json:"next_url"`
+}
+
+type AddIllustComment struct {
+	Comment Comment `json:"comment"`
+}
+
+type Comment struct {
+	ID      int    `json:"id"`
+	Comment string `json:"comment"`
+	Date    string `json:"date"`
+	// User.IsFollowed is not returned for comments and is always false.
+	User IllustUser `json:"user"`
+	// Stamp is set, and Comment is empty, if the comment is a stamp.
+	Stamp      *CommentStamp `json:"stamp"`
+	HasReplies bool          `json:"has_replies"`
+}
+
+// CreatedAt parses Date, which is in RFC 3339 format with a +09:00 offset.
+func (c Comment) CreatedAt() (time.Time, error) {
+	return time.Parse(time.RFC3339, c.Date)
+}
+
+type CommentStamp struct {
+	StampID  int    `json:"stamp_id"`
+	StampURL string `json:"stamp_url"`
+}
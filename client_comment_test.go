@@ -0,0 +1,298 @@
+package pixiv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestClient_GetIllustComments(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := newFixtureServer(t, http.MethodGet, "/v3/illust/comments", url.Values{
+		"illust_id":              []string{"105373621"},
+		"include_total_comments": []string{"true"},
+	}, fixture("fixtures/get_illust_comments.json"))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	comments, err := cli.GetIllustComments(
+		context.TODO(),
+		NewGetIllustCommentsParams().SetIllustID(105373621).SetIncludeTotalComments(true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &GetIllustComments{
+		TotalComments: 3,
+		Comments: []Comment{
+			{
+				ID:      151234567,
+				Comment: "色使いが素敵です！",
+				Date:    "2023-02-09T20:11:45+09:00",
+				User: IllustUser{
+					ID:      27517,
+					Name:    "みかん",
+					Account: "mikan_27",
+					ProfileImageURLs: map[string]string{
+						"medium": "https://i.pximg.net/user-profile/img/2020/04/12/01/02/03/27517_170.jpg",
+					},
+				},
+				HasReplies: true,
+			},
+			{
+				ID:   151234500,
+				Date: "2023-02-09T19:58:02+09:00",
+				User: IllustUser{
+					ID:      1190120,
+					Name:    "ねこ",
+					Account: "neko1190",
+					ProfileImageURLs: map[string]string{
+						"medium": "https://i.pximg.net/user-profile/img/2019/08/30/22/10/45/1190120_170.jpg",
+					},
+				},
+				Stamp: &CommentStamp{
+					StampID:  301,
+					StampURL: "https://s.pximg.net/common/images/stamp/generated-stamps/301_s.jpg",
+				},
+			},
+		},
+	}
+	if g, e := comments, expected; !reflect.DeepEqual(g, e) {
+		t.Errorf("got %#v, want %#v", g, e)
+	}
+
+	if _, err := cli.GetIllustComments(context.TODO(), NewGetIllustCommentsParams()); !isErrInvalidParams(err) {
+		t.Errorf("GetIllustComments() should return an *ErrInvalidParams if IllustID is missing")
+	}
+}
+
+func TestClient_GetIllustCommentReplies(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := newFixtureServer(t, http.MethodGet, "/v2/illust/comment/replies", url.Values{
+		"comment_id": []string{"151234567"},
+	}, fixture("fixtures/get_illust_comment_replies.json"))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	replies, err := cli.GetIllustCommentReplies(context.TODO(), NewGetIllustCommentRepliesParams().SetCommentID(151234567))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := len(replies.Comments), 1; g != e {
+		t.Fatalf("got %d comments, want %d", g, e)
+	}
+
+	if g, e := replies.Comments[0].Comment, "ありがとうございます！"; g != e {
+		t.Errorf("got Comments[0].Comment %q, want %q", g, e)
+	}
+
+	if g, e := replies.Comments[0].User.Account, "fuzichoco"; g != e {
+		t.Errorf("got Comments[0].User.Account %q, want %q", g, e)
+	}
+
+	if _, err := cli.GetIllustCommentReplies(context.TODO(), NewGetIllustCommentRepliesParams()); !isErrInvalidParams(err) {
+		t.Errorf("GetIllustCommentReplies() should return an *ErrInvalidParams if CommentID is missing")
+	}
+}
+
+func TestClient_AddIllustComment(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := newFixtureServer(t, http.MethodPost, "/v1/illust/comment/add", url.Values{
+		"illust_id":         []string{"105373621"},
+		"comment":           []string{"夜空がきれい"},
+		"parent_comment_id": []string{"151234567"},
+	}, fixture("fixtures/add_illust_comment.json"))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	added, err := cli.AddIllustComment(
+		context.TODO(),
+		NewAddIllustCommentParams().SetIllustID(105373621).SetComment("夜空がきれい").SetParentCommentID(151234567),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := added.Comment.ID, 151250000; g != e {
+		t.Errorf("got Comment.ID %d, want %d", g, e)
+	}
+
+	if g, e := added.Comment.Comment, "夜空がきれい"; g != e {
+		t.Errorf("got Comment.Comment %q, want %q", g, e)
+	}
+}
+
+func TestAddIllustCommentParams_Validate(t *testing.T) {
+	cases := []struct {
+		name   string
+		params *AddIllustCommentParams
+		fields []string
+	}{
+		{
+			name:   "missing fields",
+			params: NewAddIllustCommentParams(),
+			fields: []string{"IllustID", "Comment"},
+		},
+		{
+			name:   "empty comment",
+			params: NewAddIllustCommentParams().SetIllustID(1).SetComment(""),
+			fields: []string{"Comment"},
+		},
+		{
+			name:   "comment and stamp",
+			params: NewAddIllustCommentParams().SetIllustID(1).SetComment("a").SetStampID(301),
+			fields: []string{"Comment"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err, ok := c.params.Validate().(*ErrInvalidParams)
+			if !ok {
+				t.Fatalf("Validate() should return an *ErrInvalidParams")
+			}
+
+			fields := []string{}
+			for _, e := range err.Errs {
+				fields = append(fields, e.Field)
+			}
+
+			if g, e := fields, c.fields; !reflect.DeepEqual(g, e) {
+				t.Errorf("got invalid fields %q, want %q", g, e)
+			}
+		})
+	}
+
+	if err := NewAddIllustCommentParams().SetIllustID(1).SetStampID(301).Validate(); err != nil {
+		t.Errorf("Validate() should accept a stamp without a comment, got %v", err)
+	}
+}
+
+func TestComment_CreatedAt(t *testing.T) {
+	comment := Comment{Date: "2023-02-09T20:11:45+09:00"}
+
+	createdAt, err := comment.CreatedAt()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := createdAt, time.Date(2023, 2, 9, 11, 11, 45, 0, time.UTC); !g.Equal(e) {
+		t.Errorf("got %v, want %v", g, e)
+	}
+
+	if _, offset := createdAt.Zone(); offset != 9*60*60 {
+		t.Errorf("got zone offset %d, want %d", offset, 9*60*60)
+	}
+
+	if _, err := (Comment{}).CreatedAt(); err == nil {
+		t.Errorf("CreatedAt() should return an error if Date is empty")
+	}
+}
+
+// newCommentTreeServer serves the comments of any illust from roots and the
+// replies to a comment from replies, one comment per page.
+func newCommentTreeServer(roots []int, replies map[int][]int) *httptest.Server {
+	var ts *httptest.Server
+
+	page := func(w http.ResponseWriter, r *http.Request, ids []int, path string, key string) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+		result := GetIllustCommentReplies{}
+		if offset < len(ids) {
+			id := ids[offset]
+			result.Comments = []Comment{{ID: id, Comment: strconv.Itoa(id), HasReplies: len(replies[id]) > 0}}
+		}
+		if offset+1 < len(ids) {
+			result.NextURL = fmt.Sprintf("%s%s?%s=%s&offset=%d", ts.URL, path, key, r.URL.Query().Get(key), offset+1)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/illust/comments", func(w http.ResponseWriter, r *http.Request) {
+		page(w, r, roots, "/v3/illust/comments", "illust_id")
+	})
+	mux.HandleFunc("/v2/illust/comment/replies", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.Atoi(r.URL.Query().Get("comment_id"))
+		page(w, r, replies[id], "/v2/illust/comment/replies", "comment_id")
+	})
+
+	ts = httptest.NewServer(mux)
+	return ts
+}
+
+func TestClient_WalkIllustComments(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := newCommentTreeServer([]int{1, 2, 3}, map[int][]int{
+		1:  {11, 12},
+		12: {121},
+		3:  {31},
+	})
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	visited := []string{}
+	err := cli.WalkIllustComments(context.TODO(), 105373621, func(comment Comment, parents []Comment) error {
+		path := ""
+		for _, parent := range parents {
+			path += parent.Comment + "/"
+		}
+		visited = append(visited, path+comment.Comment)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"1", "1/11", "1/12", "1/12/121", "2", "3", "3/31"}
+	if g, e := visited, expected; !reflect.DeepEqual(g, e) {
+		t.Errorf("got visited comments %q, want %q", g, e)
+	}
+}
+
+func TestClient_WalkIllustComments_Stop(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := newCommentTreeServer([]int{1, 2}, map[int][]int{1: {11, 12}})
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	errStop := errors.New("stop")
+
+	visited := []int{}
+	err := cli.WalkIllustComments(context.TODO(), 105373621, func(comment Comment, parents []Comment) error {
+		visited = append(visited, comment.ID)
+		if comment.ID == 11 {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop {
+		t.Fatalf("got error %v, want %v", err, errStop)
+	}
+
+	if g, e := visited, []int{1, 11}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got visited comments %v, want %v", g, e)
+	}
+}
@@ -0,0 +1,179 @@
+package pixiv
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// RestrictAll includes both public and private follows. It is only accepted
+// by GetIllustFollow.
+const RestrictAll = "all"
+
+const (
+	ContentTypeIllust = "illust"
+	ContentTypeManga  = "manga"
+)
+
+type GetIllustFollowParams struct {
+	Restrict *string
+	Offset   *int
+}
+
+func NewGetIllustFollowParams() *GetIllustFollowParams {
+	return &GetIllustFollowParams{}
+}
+
+func (p *GetIllustFollowParams) SetRestrict(restrict string) *GetIllustFollowParams {
+	p.Restrict = &restrict
+	return p
+}
+
+func (p *GetIllustFollowParams) SetOffset(offset int) *GetIllustFollowParams {
+	p.Offset = &offset
+	return p
+}
+
+func (p *GetIllustFollowParams) Validate() error {
+	err := &ErrInvalidParams{}
+
+	if p.Restrict != nil && *p.Restrict != RestrictPublic && *p.Restrict != RestrictPrivate && *p.Restrict != RestrictAll {
+		err.Add(ErrInvalidParam{"Restrict", "must be one of \"public\", \"private\" or \"all\""})
+	}
+
+	if err.Len() > 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (p *GetIllustFollowParams) buildQuery() string {
+	v := url.Values{}
+
+	if p.Restrict != nil {
+		v.Set("restrict", *p.Restrict)
+	} else {
+		v.Set("restrict", RestrictPublic)
+	}
+
+	if p.Offset != nil {
+		v.Set("offset", strconv.Itoa(*p.Offset))
+	}
+
+	return v.Encode()
+}
+
+// GetIllustFollow returns the latest works of the users followed by the
+// logged-in user, newest first.
+func (c *Client) GetIllustFollow(ctx context.Context, params *GetIllustFollowParams) (*GetIllustFollow, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result GetIllustFollow
+
+	if err := c.get(ctx, c.baseURL()+"/v2/illust/follow?"+params.buildQuery(), &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (c *Client) GetIllustFollowNext(ctx context.Context, nextURL string) (*GetIllustFollow, error) {
+	var result GetIllustFollow
+
+	if err := c.get(ctx, nextURL, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+type GetIllustNewParams struct {
+	ContentType *string
+	MaxIllustID *int
+	Filter      *string
+}
+
+func NewGetIllustNewParams() *GetIllustNewParams {
+	return &GetIllustNewParams{}
+}
+
+func (p *GetIllustNewParams) SetContentType(contentType string) *GetIllustNewParams {
+	p.ContentType = &contentType
+	return p
+}
+
+// SetMaxIllustID limits the result to works with an ID of at most
+// maxIllustID. The following pages use it instead of an offset.
+func (p *GetIllustNewParams) SetMaxIllustID(maxIllustID int) *GetIllustNewParams {
+	p.MaxIllustID = &maxIllustID
+	return p
+}
+
+func (p *GetIllustNewParams) SetFilter(filter string) *GetIllustNewParams {
+	p.Filter = &filter
+	return p
+}
+
+func (p *GetIllustNewParams) Validate() error {
+	err := &ErrInvalidParams{}
+
+	if p.ContentType != nil && *p.ContentType != ContentTypeIllust && *p.ContentType != ContentTypeManga {
+		err.Add(ErrInvalidParam{"ContentType", "must be either \"illust\" or \"manga\""})
+	}
+
+	if err.Len() > 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (p *GetIllustNewParams) buildQuery() string {
+	v := url.Values{}
+
+	if p.ContentType != nil {
+		v.Set("content_type", *p.ContentType)
+	} else {
+		v.Set("content_type", ContentTypeIllust)
+	}
+
+	if p.MaxIllustID != nil {
+		v.Set("max_illust_id", strconv.Itoa(*p.MaxIllustID))
+	}
+
+	if p.Filter != nil {
+		v.Set("filter", *p.Filter)
+	} else {
+		v.Set("filter", "for_android")
+	}
+
+	return v.Encode()
+}
+
+// GetIllustNew returns the latest works posted on pixiv, newest first.
+func (c *Client) GetIllustNew(ctx context.Context, params *GetIllustNewParams) (*GetIllustNew, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result GetIllustNew
+
+	if err := c.get(ctx, c.baseURL()+"/v1/illust/new?"+params.buildQuery(), &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (c *Client) GetIllustNewNext(ctx context.Context, nextURL string) (*GetIllustNew, error) {
+	var result GetIllustNew
+
+	if err := c.get(ctx, nextURL, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
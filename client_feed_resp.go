@@ -0,0 +1,11 @@
+package pixiv
+
+type GetIllustFollow struct {
+	Illusts []Illust `json:"illusts"`
+	NextURL string   `json:"next_url"`
+}
+
+type GetIllustNew struct {
+	Illusts []Illust `json:"illusts"`
+	NextURL string   `json:"next_url"`
+}
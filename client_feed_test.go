@@ -0,0 +1,71 @@
+package pixiv
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestClient_GetIllustFollow(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := newFixtureServer(t, http.MethodGet, "/v2/illust/follow", url.Values{
+		"restrict": []string{"all"},
+	}, fixture("fixtures/get_illust_follow.json"))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	follow, err := cli.GetIllustFollow(context.TODO(), NewGetIllustFollowParams().SetRestrict(RestrictAll))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := illustIDs(follow.Illusts), []int{105420003, 105410002, 105400001}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got Illusts IDs %v, want %v", g, e)
+	}
+
+	if g, e := follow.Illusts[0].User.Account, "fuzichoco"; g != e {
+		t.Errorf("got Illusts[0].User.Account %q, want %q", g, e)
+	}
+
+	if g, e := follow.NextURL, "https://app-api.pixiv.net/v2/illust/follow?restrict=public&offset=30"; g != e {
+		t.Errorf("got NextURL %q, want %q", g, e)
+	}
+
+	if _, err := cli.GetIllustFollow(context.TODO(), NewGetIllustFollowParams().SetRestrict("mypixiv")); !isErrInvalidParams(err) {
+		t.Errorf("GetIllustFollow() should return an *ErrInvalidParams if Restrict is invalid")
+	}
+}
+
+func TestClient_GetIllustNew(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := newFixtureServer(t, http.MethodGet, "/v1/illust/new", url.Values{
+		"content_type":  []string{"illust"},
+		"max_illust_id": []string{"105430011"},
+		"filter":        []string{"for_android"},
+	}, fixture("fixtures/get_illust_new.json"))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	newIllusts, err := cli.GetIllustNew(context.TODO(), NewGetIllustNewParams().SetMaxIllustID(105430011))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := illustIDs(newIllusts.Illusts), []int{105430011, 105430007}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got Illusts IDs %v, want %v", g, e)
+	}
+
+	if g, e := newIllusts.NextURL, "https://app-api.pixiv.net/v1/illust/new?content_type=illust&filter=for_android&max_illust_id=105430006"; g != e {
+		t.Errorf("got NextURL %q, want %q", g, e)
+	}
+
+	if _, err := cli.GetIllustNew(context.TODO(), NewGetIllustNewParams().SetContentType("ugoira")); !isErrInvalidParams(err) {
+		t.Errorf("GetIllustNew() should return an *ErrInvalidParams if ContentType is invalid")
+	}
+}
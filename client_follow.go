@@ -0,0 +1,349 @@
+package pixiv
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+type GetUserFollowingParams struct {
+	UserID   *int
+	Restrict *string
+	Offset   *int
+}
+
+func NewGetUserFollowingParams() *GetUserFollowingParams {
+	return &GetUserFollowingParams{}
+}
+
+func (p *GetUserFollowingParams) SetUserID(userID int) *GetUserFollowingParams {
+	p.UserID = &userID
+	return p
+}
+
+func (p *GetUserFollowingParams) SetRestrict(restrict string) *GetUserFollowingParams {
+	p.Restrict = &restrict
+	return p
+}
+
+func (p *GetUserFollowingParams) SetOffset(offset int) *GetUserFollowingParams {
+	p.Offset = &offset
+	return p
+}
+
+func (p *GetUserFollowingParams) Validate() error {
+	err := &ErrInvalidParams{}
+
+	if p.UserID == nil {
+		err.Add(ErrInvalidParam{"UserID", "missing required field"})
+	}
+
+	if p.Restrict != nil && *p.Restrict != RestrictPublic && *p.Restrict != RestrictPrivate {
+		err.Add(ErrInvalidParam{"Restrict", "must be either \"public\" or \"private\""})
+	}
+
+	if err.Len() > 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (p *GetUserFollowingParams) buildQuery() string {
+	v := url.Values{}
+
+	v.Set("user_id", strconv.Itoa(*p.UserID))
+
+	if p.Restrict != nil {
+		v.Set("restrict", *p.Restrict)
+	} else {
+		v.Set("restrict", RestrictPublic)
+	}
+
+	if p.Offset != nil {
+		v.Set("offset", strconv.Itoa(*p.Offset))
+	}
+
+	return v.Encode()
+}
+
+// GetUserFollowing returns the users followed by a user. Private follows
+// are only visible to the user themselves.
+func (c *Client) GetUserFollowing(ctx context.Context, params *GetUserFollowingParams) (*GetUserFollowing, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result GetUserFollowing
+
+	if err := c.get(ctx, c.baseURL()+"/v1/user/following?"+params.buildQuery(), &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (c *Client) GetUserFollowingNext(ctx context.Context, nextURL string) (*GetUserFollowing, error) {
+	var result GetUserFollowing
+
+	if err := c.get(ctx, nextURL, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+type GetUserFollowerParams struct {
+	UserID *int
+	Offset *int
+	Filter *string
+}
+
+func NewGetUserFollowerParams() *GetUserFollowerParams {
+	return &GetUserFollowerParams{}
+}
+
+func (p *GetUserFollowerParams) SetUserID(userID int) *GetUserFollowerParams {
+	p.UserID = &userID
+	return p
+}
+
+func (p *GetUserFollowerParams) SetOffset(offset int) *GetUserFollowerParams {
+	p.Offset = &offset
+	return p
+}
+
+func (p *GetUserFollowerParams) SetFilter(filter string) *GetUserFollowerParams {
+	p.Filter = &filter
+	return p
+}
+
+func (p *GetUserFollowerParams) Validate() error {
+	err := &ErrInvalidParams{}
+
+	if p.UserID == nil {
+		err.Add(ErrInvalidParam{"UserID", "missing required field"})
+	}
+
+	if err.Len() > 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (p *GetUserFollowerParams) buildQuery() string {
+	v := url.Values{}
+
+	v.Set("user_id", strconv.Itoa(*p.UserID))
+
+	if p.Offset != nil {
+		v.Set("offset", strconv.Itoa(*p.Offset))
+	}
+
+	if p.Filter != nil {
+		v.Set("filter", *p.Filter)
+	} else {
+		v.Set("filter", "for_android")
+	}
+
+	return v.Encode()
+}
+
+func (c *Client) GetUserFollower(ctx context.Context, params *GetUserFollowerParams) (*GetUserFollower, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result GetUserFollower
+
+	if err := c.get(ctx, c.baseURL()+"/v1/user/follower?"+params.buildQuery(), &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (c *Client) GetUserFollowerNext(ctx context.Context, nextURL string) (*GetUserFollower, error) {
+	var result GetUserFollower
+
+	if err := c.get(ctx, nextURL, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+type GetUserMypixivParams struct {
+	UserID *int
+	Offset *int
+}
+
+func NewGetUserMypixivParams() *GetUserMypixivParams {
+	return &GetUserMypixivParams{}
+}
+
+func (p *GetUserMypixivParams) SetUserID(userID int) *GetUserMypixivParams {
+	p.UserID = &userID
+	return p
+}
+
+func (p *GetUserMypixivParams) SetOffset(offset int) *GetUserMypixivParams {
+	p.Offset = &offset
+	return p
+}
+
+func (p *GetUserMypixivParams) Validate() error {
+	err := &ErrInvalidParams{}
+
+	if p.UserID == nil {
+		err.Add(ErrInvalidParam{"UserID", "missing required field"})
+	}
+
+	if err.Len() > 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (p *GetUserMypixivParams) buildQuery() string {
+	v := url.Values{}
+
+	v.Set("user_id", strconv.Itoa(*p.UserID))
+
+	if p.Offset != nil {
+		v.Set("offset", strconv.Itoa(*p.Offset))
+	}
+
+	return v.Encode()
+}
+
+// GetUserMypixiv returns the My pixiv friends of a user.
+func (c *Client) GetUserMypixiv(ctx context.Context, params *GetUserMypixivParams) (*GetUserMypixiv, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result GetUserMypixiv
+
+	if err := c.get(ctx, c.baseURL()+"/v1/user/mypixiv?"+params.buildQuery(), &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (c *Client) GetUserMypixivNext(ctx context.Context, nextURL string) (*GetUserMypixiv, error) {
+	var result GetUserMypixiv
+
+	if err := c.get(ctx, nextURL, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+type AddUserFollowParams struct {
+	UserID   *int
+	Restrict *string
+}
+
+func NewAddUserFollowParams() *AddUserFollowParams {
+	return &AddUserFollowParams{}
+}
+
+func (p *AddUserFollowParams) SetUserID(userID int) *AddUserFollowParams {
+	p.UserID = &userID
+	return p
+}
+
+func (p *AddUserFollowParams) SetRestrict(restrict string) *AddUserFollowParams {
+	p.Restrict = &restrict
+	return p
+}
+
+func (p *AddUserFollowParams) Validate() error {
+	err := &ErrInvalidParams{}
+
+	if p.UserID == nil {
+		err.Add(ErrInvalidParam{"UserID", "missing required field"})
+	}
+
+	if p.Restrict != nil && *p.Restrict != RestrictPublic && *p.Restrict != RestrictPrivate {
+		err.Add(ErrInvalidParam{"Restrict", "must be either \"public\" or \"private\""})
+	}
+
+	if err.Len() > 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (p *AddUserFollowParams) buildForm() url.Values {
+	v := url.Values{}
+
+	v.Set("user_id", strconv.Itoa(*p.UserID))
+
+	if p.Restrict != nil {
+		v.Set("restrict", *p.Restrict)
+	} else {
+		v.Set("restrict", RestrictPublic)
+	}
+
+	return v
+}
+
+// AddUserFollow follows a user, or changes the restrict of an existing
+// follow.
+func (c *Client) AddUserFollow(ctx context.Context, params *AddUserFollowParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	return c.post(ctx, c.baseURL()+"/v1/user/follow/add", params.buildForm(), nil)
+}
+
+type DeleteUserFollowParams struct {
+	UserID *int
+}
+
+func NewDeleteUserFollowParams() *DeleteUserFollowParams {
+	return &DeleteUserFollowParams{}
+}
+
+func (p *DeleteUserFollowParams) SetUserID(userID int) *DeleteUserFollowParams {
+	p.UserID = &userID
+	return p
+}
+
+func (p *DeleteUserFollowParams) Validate() error {
+	err := &ErrInvalidParams{}
+
+	if p.UserID == nil {
+		err.Add(ErrInvalidParam{"UserID", "missing required field"})
+	}
+
+	if err.Len() > 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (p *DeleteUserFollowParams) buildForm() url.Values {
+	v := url.Values{}
+
+	v.Set("user_id", strconv.Itoa(*p.UserID))
+
+	return v
+}
+
+func (c *Client) DeleteUserFollow(ctx context.Context, params *DeleteUserFollowParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	return c.post(ctx, c.baseURL()+"/v1/user/follow/delete", params.buildForm(), nil)
+}
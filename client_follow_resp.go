@@ -0,0 +1,27 @@
+package pixiv
+
+import "encoding/json"
+
+type GetUserFollowing struct {
+	UserPreviews []UserPreview `json:"user_previews"`
+	NextURL      string        `json:"next_url"`
+}
+
+type GetUserFollower struct {
+	UserPreviews []UserPreview `json:"user_previews"`
+	NextURL      string        `json:"next_url"`
+}
+
+type GetUserMypixiv struct {
+	UserPreviews []UserPreview `json:"user_previews"`
+	NextURL      string        `json:"next_url"`
+}
+
+// UserPreview is a user along with a few of their latest works.
+type UserPreview struct {
+	User    IllustUser `json:"user"`
+	Illusts []Illust   `json:"illusts"`
+	// Novels are left undecoded, as this package does not model novels.
+	Novels  []json.RawMessage `json:"novels"`
+	IsMuted bool              `json:"is_muted"`
+}
@@ -0,0 +1,170 @@
+package pixiv
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestClient_GetUserFollowing(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := newFixtureServer(t, http.MethodGet, "/v1/user/following", url.Values{
+		"user_id":  []string{"1"},
+		"restrict": []string{"private"},
+		"offset":   []string{"30"},
+	}, fixture("fixtures/get_user_following.json"))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	following, err := cli.GetUserFollowing(
+		context.TODO(),
+		NewGetUserFollowingParams().SetUserID(1).SetRestrict(RestrictPrivate).SetOffset(30),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := len(following.UserPreviews), 2; g != e {
+		t.Fatalf("got %d user previews, want %d", g, e)
+	}
+
+	preview0 := following.UserPreviews[0]
+
+	expectedUser := IllustUser{
+		ID:      10851340,
+		Name:    "藤ちょこ",
+		Account: "fuzichoco",
+		ProfileImageURLs: map[string]string{
+			"medium": "https://i.pximg.net/user-profile/img/2022/11/02/10/01/33/10851340_170.jpg",
+		},
+		IsFollowed: true,
+	}
+	if g, e := preview0.User, expectedUser; !reflect.DeepEqual(g, e) {
+		t.Errorf("got UserPreviews[0].User %#v, want %#v", g, e)
+	}
+
+	illustIDs := []int{}
+	for _, illust := range preview0.Illusts {
+		illustIDs = append(illustIDs, illust.ID)
+	}
+	if g, e := illustIDs, []int{105373621, 105012345}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got UserPreviews[0].Illusts IDs %v, want %v", g, e)
+	}
+
+	if g, e := preview0.Illusts[0].User.Account, "fuzichoco"; g != e {
+		t.Errorf("got UserPreviews[0].Illusts[0].User.Account %q, want %q", g, e)
+	}
+
+	if g, e := following.UserPreviews[1].IsMuted, true; g != e {
+		t.Errorf("got UserPreviews[1].IsMuted %v, want %v", g, e)
+	}
+
+	if g, e := following.NextURL, "https://app-api.pixiv.net/v1/user/following?user_id=1&restrict=public&offset=30"; g != e {
+		t.Errorf("got NextURL %q, want %q", g, e)
+	}
+
+	if _, err := cli.GetUserFollowing(context.TODO(), NewGetUserFollowingParams().SetUserID(1).SetRestrict("all")); !isErrInvalidParams(err) {
+		t.Errorf("GetUserFollowing() should return an *ErrInvalidParams if Restrict is invalid")
+	}
+}
+
+func TestClient_GetUserFollower(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := newFixtureServer(t, http.MethodGet, "/v1/user/follower", url.Values{
+		"user_id": []string{"1"},
+		"filter":  []string{"for_android"},
+	}, fixture("fixtures/get_user_follower.json"))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	follower, err := cli.GetUserFollower(context.TODO(), NewGetUserFollowerParams().SetUserID(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := len(follower.UserPreviews), 1; g != e {
+		t.Fatalf("got %d user previews, want %d", g, e)
+	}
+
+	if g, e := follower.UserPreviews[0].User.IsFollowed, false; g != e {
+		t.Errorf("got UserPreviews[0].User.IsFollowed %v, want %v", g, e)
+	}
+
+	if g, e := follower.UserPreviews[0].Illusts[0].Title, "練習"; g != e {
+		t.Errorf("got UserPreviews[0].Illusts[0].Title %q, want %q", g, e)
+	}
+
+	if g, e := follower.NextURL, ""; g != e {
+		t.Errorf("got NextURL %q, want %q", g, e)
+	}
+}
+
+func TestClient_GetUserMypixiv(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := newFixtureServer(t, http.MethodGet, "/v1/user/mypixiv", url.Values{
+		"user_id": []string{"1"},
+	}, fixture("fixtures/get_user_mypixiv.json"))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	mypixiv, err := cli.GetUserMypixiv(context.TODO(), NewGetUserMypixivParams().SetUserID(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := len(mypixiv.UserPreviews), 1; g != e {
+		t.Fatalf("got %d user previews, want %d", g, e)
+	}
+
+	if g, e := mypixiv.UserPreviews[0].User.Account, "alice810"; g != e {
+		t.Errorf("got UserPreviews[0].User.Account %q, want %q", g, e)
+	}
+
+	if _, err := cli.GetUserMypixiv(context.TODO(), NewGetUserMypixivParams()); !isErrInvalidParams(err) {
+		t.Errorf("GetUserMypixiv() should return an *ErrInvalidParams if UserID is missing")
+	}
+}
+
+func TestClient_AddUserFollow(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := newFixtureServer(t, http.MethodPost, "/v1/user/follow/add", url.Values{
+		"user_id":  []string{"10851340"},
+		"restrict": []string{"private"},
+	}, nil)
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	if err := cli.AddUserFollow(context.TODO(), NewAddUserFollowParams().SetUserID(10851340).SetRestrict(RestrictPrivate)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_DeleteUserFollow(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := newFixtureServer(t, http.MethodPost, "/v1/user/follow/delete", url.Values{
+		"user_id": []string{"10851340"},
+	}, nil)
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	if err := cli.DeleteUserFollow(context.TODO(), NewDeleteUserFollowParams().SetUserID(10851340)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func isErrInvalidParams(err error) bool {
+	_, ok := err.(*ErrInvalidParams)
+	return ok
+}
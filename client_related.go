@@ -0,0 +1,257 @@
+package pixiv
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type GetIllustRelatedParams struct {
+	IllustID      *int
+	SeedIllustIDs []int
+	Filter        *string
+}
+
+func NewGetIllustRelatedParams() *GetIllustRelatedParams {
+	return &GetIllustRelatedParams{}
+}
+
+func (p *GetIllustRelatedParams) SetIllustID(illustID int) *GetIllustRelatedParams {
+	p.IllustID = &illustID
+	return p
+}
+
+// SetSeedIllustIDs sets the illusts already shown as related, which the API
+// uses to vary the following pages.
+func (p *GetIllustRelatedParams) SetSeedIllustIDs(seedIllustIDs []int) *GetIllustRelatedParams {
+	p.SeedIllustIDs = seedIllustIDs
+	return p
+}
+
+func (p *GetIllustRelatedParams) SetFilter(filter string) *GetIllustRelatedParams {
+	p.Filter = &filter
+	return p
+}
+
+func (p *GetIllustRelatedParams) Validate() error {
+	err := &ErrInvalidParams{}
+
+	if p.IllustID == nil {
+		err.Add(ErrInvalidParam{"IllustID", "missing required field"})
+	}
+
+	if err.Len() > 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (p *GetIllustRelatedParams) buildQuery() string {
+	v := url.Values{}
+
+	v.Set("illust_id", strconv.Itoa(*p.IllustID))
+
+	for _, id := range p.SeedIllustIDs {
+		v.Add("seed_illust_ids[]", strconv.Itoa(id))
+	}
+
+	if p.Filter != nil {
+		v.Set("filter", *p.Filter)
+	} else {
+		v.Set("filter", "for_android")
+	}
+
+	return v.Encode()
+}
+
+func (c *Client) GetIllustRelated(ctx context.Context, params *GetIllustRelatedParams) (*GetIllustRelated, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result GetIllustRelated
+
+	if err := c.get(ctx, c.baseURL()+"/v2/illust/related?"+params.buildQuery(), &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (c *Client) GetIllustRelatedNext(ctx context.Context, nextURL string) (*GetIllustRelated, error) {
+	var result GetIllustRelated
+
+	if err := c.get(ctx, nextURL, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+type GetIllustRecommendedParams struct {
+	IncludeRankingIllusts *bool
+	BookmarkIllustIDs     []int
+	Offset                *int
+	Filter                *string
+}
+
+func NewGetIllustRecommendedParams() *GetIllustRecommendedParams {
+	return &GetIllustRecommendedParams{}
+}
+
+func (p *GetIllustRecommendedParams) SetIncludeRankingIllusts(includeRankingIllusts bool) *GetIllustRecommendedParams {
+	p.IncludeRankingIllusts = &includeRankingIllusts
+	return p
+}
+
+// SetBookmarkIllustIDs sets the illusts that recommendations are based on
+// instead of the bookmarks of the logged-in user.
+func (p *GetIllustRecommendedParams) SetBookmarkIllustIDs(bookmarkIllustIDs []int) *GetIllustRecommendedParams {
+	p.BookmarkIllustIDs = bookmarkIllustIDs
+	return p
+}
+
+func (p *GetIllustRecommendedParams) SetOffset(offset int) *GetIllustRecommendedParams {
+	p.Offset = &offset
+	return p
+}
+
+func (p *GetIllustRecommendedParams) SetFilter(filter string) *GetIllustRecommendedParams {
+	p.Filter = &filter
+	return p
+}
+
+func (p *GetIllustRecommendedParams) Validate() error {
+	return nil
+}
+
+func (p *GetIllustRecommendedParams) buildQuery() string {
+	v := url.Values{}
+
+	if p.IncludeRankingIllusts != nil {
+		v.Set("include_ranking_illusts", strconv.FormatBool(*p.IncludeRankingIllusts))
+	}
+
+	if len(p.BookmarkIllustIDs) > 0 {
+		v.Set("bookmark_illust_ids", joinIDs(p.BookmarkIllustIDs))
+	}
+
+	if p.Offset != nil {
+		v.Set("offset", strconv.Itoa(*p.Offset))
+	}
+
+	if p.Filter != nil {
+		v.Set("filter", *p.Filter)
+	} else {
+		v.Set("filter", "for_android")
+	}
+
+	return v.Encode()
+}
+
+func (c *Client) GetIllustRecommended(ctx context.Context, params *GetIllustRecommendedParams) (*GetIllustRecommended, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result GetIllustRecommended
+
+	if err := c.get(ctx, c.baseURL()+"/v1/illust/recommended?"+params.buildQuery(), &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (c *Client) GetIllustRecommendedNext(ctx context.Context, nextURL string) (*GetIllustRecommended, error) {
+	var result GetIllustRecommended
+
+	if err := c.get(ctx, nextURL, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+type GetMangaRecommendedParams struct {
+	BookmarkIllustIDs []int
+	Offset            *int
+	Filter            *string
+}
+
+func NewGetMangaRecommendedParams() *GetMangaRecommendedParams {
+	return &GetMangaRecommendedParams{}
+}
+
+func (p *GetMangaRecommendedParams) SetBookmarkIllustIDs(bookmarkIllustIDs []int) *GetMangaRecommendedParams {
+	p.BookmarkIllustIDs = bookmarkIllustIDs
+	return p
+}
+
+func (p *GetMangaRecommendedParams) SetOffset(offset int) *GetMangaRecommendedParams {
+	p.Offset = &offset
+	return p
+}
+
+func (p *GetMangaRecommendedParams) SetFilter(filter string) *GetMangaRecommendedParams {
+	p.Filter = &filter
+	return p
+}
+
+func (p *GetMangaRecommendedParams) Validate() error {
+	return nil
+}
+
+func (p *GetMangaRecommendedParams) buildQuery() string {
+	v := url.Values{}
+
+	if len(p.BookmarkIllustIDs) > 0 {
+		v.Set("bookmark_illust_ids", joinIDs(p.BookmarkIllustIDs))
+	}
+
+	if p.Offset != nil {
+		v.Set("offset", strconv.Itoa(*p.Offset))
+	}
+
+	if p.Filter != nil {
+		v.Set("filter", *p.Filter)
+	} else {
+		v.Set("filter", "for_android")
+	}
+
+	return v.Encode()
+}
+
+func (c *Client) GetMangaRecommended(ctx context.Context, params *GetMangaRecommendedParams) (*GetMangaRecommended, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result GetMangaRecommended
+
+	if err := c.get(ctx, c.baseURL()+"/v1/manga/recommended?"+params.buildQuery(), &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (c *Client) GetMangaRecommendedNext(ctx context.Context, nextURL string) (*GetMangaRecommended, error) {
+	var result GetMangaRecommended
+
+	if err := c.get(ctx, nextURL, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func joinIDs(ids []int) string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = strconv.Itoa(id)
+	}
+	return strings.Join(s, ",")
+}
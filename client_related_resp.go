@@ -0,0 +1,32 @@
+package pixiv
+
+type GetIllustRelated struct {
+	Illusts []Illust `json:"illusts"`
+	NextURL string   `json:"next_url"`
+}
+
+// GetIllustRecommended holds recommendations in Illusts and, if requested,
+// the current ranking in RankingIllusts. Only Illusts continues on the
+// following pages.
+type GetIllustRecommended struct {
+	Illusts        []Illust      `json:"illusts"`
+	RankingIllusts []Illust      `json:"ranking_illusts"`
+	ContestExists  bool          `json:"contest_exists"`
+	PrivacyPolicy  PrivacyPolicy `json:"privacy_policy"`
+	NextURL        string        `json:"next_url"`
+}
+
+type GetMangaRecommended struct {
+	Illusts        []Illust      `json:"illusts"`
+	RankingIllusts []Illust      `json:"ranking_illusts"`
+	PrivacyPolicy  PrivacyPolicy `json:"privacy_policy"`
+	NextURL        string        `json:"next_url"`
+}
+
+// PrivacyPolicy is set when the user has to agree to an updated privacy
+// policy, and is empty otherwise.
+type PrivacyPolicy struct {
+	Version string `json:"version"`
+	Message string `json:"message"`
+	URL     string `json:"url"`
+}
@@ -0,0 +1,116 @@
+package pixiv
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestClient_GetIllustRelated(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := newFixtureServer(t, http.MethodGet, "/v2/illust/related", url.Values{
+		"illust_id":         []string{"105373621"},
+		"seed_illust_ids[]": []string{"105299871", "105188234"},
+		"filter":            []string{"for_android"},
+	}, fixture("fixtures/get_illust_related.json"))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	related, err := cli.GetIllustRelated(
+		context.TODO(),
+		NewGetIllustRelatedParams().SetIllustID(105373621).SetSeedIllustIDs([]int{105299871, 105188234}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := illustIDs(related.Illusts), []int{105299871, 105188234}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got Illusts IDs %v, want %v", g, e)
+	}
+
+	if g, e := related.NextURL, "https://app-api.pixiv.net/v2/illust/related?illust_id=105373621&filter=for_android&seed_illust_ids%5B0%5D=105299871&seed_illust_ids%5B1%5D=105188234"; g != e {
+		t.Errorf("got NextURL %q, want %q", g, e)
+	}
+
+	if _, err := cli.GetIllustRelated(context.TODO(), NewGetIllustRelatedParams()); !isErrInvalidParams(err) {
+		t.Errorf("GetIllustRelated() should return an *ErrInvalidParams if IllustID is missing")
+	}
+}
+
+func TestClient_GetIllustRecommended(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := newFixtureServer(t, http.MethodGet, "/v1/illust/recommended", url.Values{
+		"include_ranking_illusts": []string{"true"},
+		"bookmark_illust_ids":     []string{"64936066,105373621"},
+		"filter":                  []string{"for_android"},
+	}, fixture("fixtures/get_illust_recommended.json"))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	recommended, err := cli.GetIllustRecommended(
+		context.TODO(),
+		NewGetIllustRecommendedParams().SetIncludeRankingIllusts(true).SetBookmarkIllustIDs([]int{64936066, 105373621}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := illustIDs(recommended.Illusts), []int{105400001}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got Illusts IDs %v, want %v", g, e)
+	}
+
+	if g, e := illustIDs(recommended.RankingIllusts), []int{105402211, 105401100}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got RankingIllusts IDs %v, want %v", g, e)
+	}
+
+	if g, e := recommended.PrivacyPolicy, (PrivacyPolicy{}); g != e {
+		t.Errorf("got PrivacyPolicy %#v, want %#v", g, e)
+	}
+
+	if g, e := recommended.NextURL, "https://app-api.pixiv.net/v1/illust/recommended?filter=for_android&include_ranking_illusts=false&offset=30"; g != e {
+		t.Errorf("got NextURL %q, want %q", g, e)
+	}
+}
+
+func TestClient_GetMangaRecommended(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := newFixtureServer(t, http.MethodGet, "/v1/manga/recommended", url.Values{
+		"offset": []string{"30"},
+		"filter": []string{"for_android"},
+	}, fixture("fixtures/get_manga_recommended.json"))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	recommended, err := cli.GetMangaRecommended(context.TODO(), NewGetMangaRecommendedParams().SetOffset(30))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := len(recommended.Illusts), 1; g != e {
+		t.Fatalf("got %d illusts, want %d", g, e)
+	}
+
+	if g, e := recommended.Illusts[0].Type, IllustTypeManga; g != e {
+		t.Errorf("got Illusts[0].Type %q, want %q", g, e)
+	}
+
+	if g, e := len(recommended.Illusts[0].MetaPages), 2; g != e {
+		t.Errorf("got %d meta pages, want %d", g, e)
+	}
+
+	if g, e := len(recommended.RankingIllusts), 0; g != e {
+		t.Errorf("got %d ranking illusts, want %d", g, e)
+	}
+
+	if g, e := recommended.NextURL, "https://app-api.pixiv.net/v1/manga/recommended?filter=for_android&include_ranking_label=true&offset=30"; g != e {
+		t.Errorf("got NextURL %q, want %q", g, e)
+	}
+}
@@ -11,104 +11,106 @@ type APIError struct {
 	UserMessageDetails map[string]interface{} `json:"user_message_details"`
 }
 
-type GetIllustRanking struct {
-	Illusts []GetIllustRankingIllust `json:"illusts"`
-	NextURL string                   `json:"next_url"`
-}
-
-type GetIllustRankingIllust struct {
-	ID             int                              `json:"id"`
-	Title          string                           `json:"title"`
-	Type           string                           `json:"type"`
-	ImageURLs      map[string]string                `json:"image_urls"`
-	Caption        string                           `json:"caption"`
-	Restrict       int                              `json:"restrict"`
-	User           GetIllustRankingIllustUser       `json:"user"`
-	Tags           []GetIllustRankingIllustTag      `json:"tags"`
-	Tools          []string                         `json:"tools"`
-	CreateDate     string                           `json:"create_date"`
-	PageCount      int                              `json:"page_count"`
-	Width          int                              `json:"width"`
-	Height         int                              `json:"height"`
-	SanityLevel    int                              `json:"sanity_level"`
-	Series         GetIllustRankingIllustSeries     `json:"series"`
-	MetaSinglePage map[string]string                `json:"meta_single_page"`
-	MetaPages      []GetIllustRankingIllustMetaPage `json:"meta_pages"`
-	TotalView      int                              `json:"total_view"`
-	TotalBookmarks int                              `json:"total_bookmarks"`
-	IsBookmarked   bool                             `json:"is_bookmarked"`
-	Visible        bool                             `json:"visible"`
-	IsMuted        bool                             `json:"is_muted"`
+// Illust is a work as returned by every endpoint that lists or describes
+// illusts.
+type Illust struct {
+	ID                    int               `json:"id"`
+	Title                 string            `json:"title"`
+	Type                  IllustType        `json:"type"`
+	ImageURLs             ImageURLs         `json:"image_urls"`
+	Caption               string            `json:"caption"`
+	Restrict              IllustRestrict    `json:"restrict"`
+	User                  IllustUser        `json:"user"`
+	Tags                  []IllustTag       `json:"tags"`
+	Tools                 []string          `json:"tools"`
+	CreateDate            string            `json:"create_date"`
+	PageCount             int               `json:"page_count"`
+	Width                 int               `json:"width"`
+	Height                int               `json:"height"`
+	SanityLevel           SanityLevel       `json:"sanity_level"`
+	XRestrict             XRestrict         `json:"x_restrict"`
+	Series                IllustSeries      `json:"series"`
+	MetaSinglePage        map[string]string `json:"meta_single_page"`
+	MetaPages             []IllustMetaPage  `json:"meta_pages"`
+	TotalView             int               `json:"total_view"`
+	TotalBookmarks        int               `json:"total_bookmarks"`
+	IsBookmarked          bool              `json:"is_bookmarked"`
+	Visible               bool              `json:"visible"`
+	IsMuted               bool              `json:"is_muted"`
+	TotalComments         int               `json:"total_comments"`
+	IllustAIType          IllustAIType      `json:"illust_ai_type"`
+	IllustBookStyle       int               `json:"illust_book_style"`
+	RestrictionAttributes []string          `json:"restriction_attributes"`
 }
 
-type GetIllustRankingIllustUser struct {
-	ID               int               `json:"id"`
-	Name             string            `json:"name"`
-	Account          string            `json:"account"`
-	ProfileImageURLs map[string]string `json:"profile_image_urls"`
-	IsFollowed       bool              `json:"is_followed"`
+type IllustUser struct {
+	ID                   int               `json:"id"`
+	Name                 string            `json:"name"`
+	Account              string            `json:"account"`
+	ProfileImageURLs     map[string]string `json:"profile_image_urls"`
+	IsFollowed           bool              `json:"is_followed"`
+	IsAccessBlockingUser bool              `json:"is_access_blocking_user"`
 }
 
-type GetIllustRankingIllustTag struct {
+type IllustTag struct {
 	Name string `json:"name"`
+	// TranslatedName is nil if the tag has no translation.
+	TranslatedName *string `json:"translated_name"`
 }
 
-type GetIllustRankingIllustSeries struct {
+type IllustSeries struct {
 	ID    int    `json:"id"`
 	Title string `json:"title"`
 }
 
-type GetIllustRankingIllustMetaPage struct {
-	ImageURLs map[string]string `json:"image_urls"`
+type IllustMetaPage struct {
+	ImageURLs ImageURLs `json:"image_urls"`
 }
 
-type GetIllustDetail struct {
-	Illust GetIllustDetailIllust `json:"illust"`
+type GetIllustRanking struct {
+	Illusts []Illust `json:"illusts"`
+	NextURL string   `json:"next_url"`
 }
 
-type GetIllustDetailIllust struct {
-	ID             int                             `json:"id"`
-	Title          string                          `json:"title"`
-	Type           string                          `json:"type"`
-	ImageURLs      map[string]string               `json:"image_urls"`
-	Caption        string                          `json:"caption"`
-	Restrict       int                             `json:"restrict"`
-	User           GetIllustDetailIllustUser       `json:"user"`
-	Tags           []GetIllustDetailIllustTag      `json:"tags"`
-	Tools          []string                        `json:"tools"`
-	CreateDate     string                          `json:"create_date"`
-	PageCount      int                             `json:"page_count"`
-	Width          int                             `json:"width"`
-	Height         int                             `json:"height"`
-	SanityLevel    int                             `json:"sanity_level"`
-	Series         GetIllustDetailIllustSeries     `json:"series"`
-	MetaSinglePage map[string]string               `json:"meta_single_page"`
-	MetaPages      []GetIllustDetailIllustMetaPage `json:"meta_pages"`
-	TotalView      int                             `json:"total_view"`
-	TotalBookmarks int                             `json:"total_bookmarks"`
-	IsBookmarked   bool                            `json:"is_bookmarked"`
-	Visible        bool                            `json:"visible"`
-	IsMuted        bool                            `json:"is_muted"`
-	TotalComments  int                             `json:"total_comments"`
+type GetIllustDetail struct {
+	Illust Illust `json:"illust"`
 }
 
-type GetIllustDetailIllustUser struct {
-	ID               int               `json:"id"`
-	Name             string            `json:"name"`
-	Account          string            `json:"alice810"`
-	ProfileImageURLs map[string]string `json:"profile_image_urls"`
-	IsFollowed       bool              `json:"is_followed"`
+type SearchIllust struct {
+	Illusts         []Illust `json:"illusts"`
+	NextURL         string   `json:"next_url"`
+	SearchSpanLimit int      `json:"search_span_limit"`
 }
 
-type GetIllustDetailIllustTag struct {
-	Name string `json:"name"`
+type GetUgoiraMetadata struct {
+	UgoiraMetadata GetUgoiraMetadataUgoiraMetadata `json:"ugoira_metadata"`
 }
 
-type GetIllustDetailIllustSeries struct {
-	ID    int    `json:"id"`
-	Title string `json:"title"`
+type GetUgoiraMetadataUgoiraMetadata struct {
+	ZipURLs map[string]string                      `json:"zip_urls"`
+	Frames  []GetUgoiraMetadataUgoiraMetadataFrame `json:"frames"`
 }
 
-type GetIllustDetailIllustMetaPage struct {
-	ImageURLs map[string]string `json:"image_urls"`
+type GetUgoiraMetadataUgoiraMetadataFrame struct {
+	File  string `json:"file"`
+	Delay int    `json:"delay"`
 }
+
+// The per-endpoint illust types below are aliases of the shared Illust
+// types, kept so that existing code continues to compile.
+
+type (
+	GetIllustRankingIllust         = Illust
+	GetIllustRankingIllustUser     = IllustUser
+	GetIllustRankingIllustTag      = IllustTag
+	GetIllustRankingIllustSeries   = IllustSeries
+	GetIllustRankingIllustMetaPage = IllustMetaPage
+)
+
+type (
+	GetIllustDetailIllust         = Illust
+	GetIllustDetailIllustUser     = IllustUser
+	GetIllustDetailIllustTag      = IllustTag
+	GetIllustDetailIllustSeries   = IllustSeries
+	GetIllustDetailIllustMetaPage = IllustMetaPage
+)
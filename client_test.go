@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -20,6 +21,50 @@ func (p *mockTokenProvider) Token(_ context.Context) (string, error) {
 	return p.token, p.err
 }
 
+// newFixtureServer serves body, or "{}" if body is nil, from path. It checks
+// that requests use method and carry expected in the query, or in a
+// form-encoded body for POST requests.
+func newFixtureServer(t *testing.T, method string, path string, expected url.Values, body []byte) *httptest.Server {
+	if body == nil {
+		body = []byte("{}")
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if g, e := r.URL.Path, path; g != e {
+			t.Errorf("got URL path %q, want %q", g, e)
+		}
+
+		if g, e := r.Method, method; g != e {
+			t.Errorf("got HTTP method %q, want %q", g, e)
+		}
+
+		if method == http.MethodPost {
+			if g, e := r.Header.Get("Content-Type"), "application/x-www-form-urlencoded"; g != e {
+				t.Errorf("got Content-Type header = %q, want %q", g, e)
+			}
+
+			if err := r.ParseForm(); err != nil {
+				t.Fatal(err)
+			}
+
+			if g, e := r.PostForm, expected; !reflect.DeepEqual(g, e) {
+				t.Errorf("got form %#v, want %#v", g, e)
+			}
+
+			if g, e := r.URL.RawQuery, ""; g != e {
+				t.Errorf("got query %q, want %q", g, e)
+			}
+		} else {
+			if g, e := r.URL.Query(), expected; !reflect.DeepEqual(g, e) {
+				t.Errorf("got query %#v, want %#v", g, e)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+}
+
 func TestClient_Do_Headers(t *testing.T) {
 	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
 
@@ -89,10 +134,10 @@ func TestClient_GetIllustRanking(t *testing.T) {
 		ID:    64936066,
 		Title: "♡",
 		Type:  "illust",
-		ImageURLs: map[string]string{
-			"medium":        "https://i.pximg.net/c/540x540_70/img-master/img/2017/09/13/12/30/00/64936066_p0_master1200.jpg",
-			"large":         "https://i.pximg.net/c/600x1200_90/img-master/img/2017/09/13/12/30/00/64936066_p0_master1200.jpg",
-			"square_medium": "https://i.pximg.net/c/360x360_70/img-master/img/2017/09/13/12/30/00/64936066_p0_square1200.jpg",
+		ImageURLs: ImageURLs{
+			Medium:       "https://i.pximg.net/c/540x540_70/img-master/img/2017/09/13/12/30/00/64936066_p0_master1200.jpg",
+			Large:        "https://i.pximg.net/c/600x1200_90/img-master/img/2017/09/13/12/30/00/64936066_p0_master1200.jpg",
+			SquareMedium: "https://i.pximg.net/c/360x360_70/img-master/img/2017/09/13/12/30/00/64936066_p0_square1200.jpg",
 		},
 		Caption:  "9/12 Happy birthday!! (・８・)",
 		Restrict: 0,
@@ -140,10 +185,10 @@ func TestClient_GetIllustRanking(t *testing.T) {
 		ID:    64914849,
 		Title: "ことりちゃんHappy birthday (・8・)♡",
 		Type:  "illust",
-		ImageURLs: map[string]string{
-			"square_medium": "https://i.pximg.net/c/360x360_70/img-master/img/2017/09/12/00/00/02/64914849_p0_square1200.jpg",
-			"medium":        "https://i.pximg.net/c/540x540_70/img-master/img/2017/09/12/00/00/02/64914849_p0_master1200.jpg",
-			"large":         "https://i.pximg.net/c/600x1200_90/img-master/img/2017/09/12/00/00/02/64914849_p0_master1200.jpg",
+		ImageURLs: ImageURLs{
+			SquareMedium: "https://i.pximg.net/c/360x360_70/img-master/img/2017/09/12/00/00/02/64914849_p0_square1200.jpg",
+			Medium:       "https://i.pximg.net/c/540x540_70/img-master/img/2017/09/12/00/00/02/64914849_p0_master1200.jpg",
+			Large:        "https://i.pximg.net/c/600x1200_90/img-master/img/2017/09/12/00/00/02/64914849_p0_master1200.jpg",
 		},
 		Caption:  "ことりちゃんおめでちゅん(・8・)♡<br />仕事で忙しくてあんまり時間が取れないので８時間くらいでサラっと描きました<br /><br />『ラブライブリンガー！ＵＲ 総集編』は現在各委託店にて好評発売中<br />メロンブックス → <a href=\"http://goo.gl/5pZ2Gx\" target=\"_blank\">http://goo.gl/5pZ2Gx</a>\u3000とらのあな → <a href=\"http://goo.gl/VHqabu\" target=\"_blank\">http://goo.gl/VHqabu</a>",
 		Restrict: 0,
@@ -179,19 +224,19 @@ func TestClient_GetIllustRanking(t *testing.T) {
 		MetaSinglePage: map[string]string{},
 		MetaPages: []GetIllustRankingIllustMetaPage{
 			{
-				ImageURLs: map[string]string{
-					"original":      "https://i.pximg.net/img-original/img/2017/09/12/00/00/02/64914849_p0.jpg",
-					"square_medium": "https://i.pximg.net/c/360x360_70/img-master/img/2017/09/12/00/00/02/64914849_p0_square1200.jpg",
-					"medium":        "https://i.pximg.net/c/540x540_70/img-master/img/2017/09/12/00/00/02/64914849_p0_master1200.jpg",
-					"large":         "https://i.pximg.net/c/600x1200_90/img-master/img/2017/09/12/00/00/02/64914849_p0_master1200.jpg",
+				ImageURLs: ImageURLs{
+					Original:     "https://i.pximg.net/img-original/img/2017/09/12/00/00/02/64914849_p0.jpg",
+					SquareMedium: "https://i.pximg.net/c/360x360_70/img-master/img/2017/09/12/00/00/02/64914849_p0_square1200.jpg",
+					Medium:       "https://i.pximg.net/c/540x540_70/img-master/img/2017/09/12/00/00/02/64914849_p0_master1200.jpg",
+					Large:        "https://i.pximg.net/c/600x1200_90/img-master/img/2017/09/12/00/00/02/64914849_p0_master1200.jpg",
 				},
 			},
 			{
-				ImageURLs: map[string]string{
-					"square_medium": "https://i.pximg.net/c/360x360_70/img-master/img/2017/09/12/00/00/02/64914849_p1_square1200.jpg",
-					"medium":        "https://i.pximg.net/c/540x540_70/img-master/img/2017/09/12/00/00/02/64914849_p1_master1200.jpg",
-					"large":         "https://i.pximg.net/c/600x1200_90/img-master/img/2017/09/12/00/00/02/64914849_p1_master1200.jpg",
-					"original":      "https://i.pximg.net/img-original/img/2017/09/12/00/00/02/64914849_p1.jpg",
+				ImageURLs: ImageURLs{
+					SquareMedium: "https://i.pximg.net/c/360x360_70/img-master/img/2017/09/12/00/00/02/64914849_p1_square1200.jpg",
+					Medium:       "https://i.pximg.net/c/540x540_70/img-master/img/2017/09/12/00/00/02/64914849_p1_master1200.jpg",
+					Large:        "https://i.pximg.net/c/600x1200_90/img-master/img/2017/09/12/00/00/02/64914849_p1_master1200.jpg",
+					Original:     "https://i.pximg.net/img-original/img/2017/09/12/00/00/02/64914849_p1.jpg",
 				},
 			},
 		},
@@ -210,6 +255,57 @@ func TestClient_GetIllustRanking(t *testing.T) {
 	}
 }
 
+func TestClient_GetIllustRanking_CurrentSchema(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture("fixtures/get_illust_ranking_2.json"))
+	}))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	ranking, err := cli.GetIllustRanking(context.TODO(), NewGetIllustRankingParams().SetMode(RankingModeDayR18))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := len(ranking.Illusts), 2; g != e {
+		t.Fatalf("got %d illusts, want %d", g, e)
+	}
+
+	illust0, illust1 := ranking.Illusts[0], ranking.Illusts[1]
+
+	if g, e := illust0.XRestrict, XRestrictR18; g != e {
+		t.Errorf("got Illusts[0].XRestrict %v, want %v", g, e)
+	}
+
+	if g, e := illust0.TotalComments, 12; g != e {
+		t.Errorf("got Illusts[0].TotalComments %d, want %d", g, e)
+	}
+
+	if g, e := illust1.XRestrict, XRestrictR18G; g != e {
+		t.Errorf("got Illusts[1].XRestrict %v, want %v", g, e)
+	}
+
+	if !illust1.IsAIGenerated() {
+		t.Errorf("Illusts[1].IsAIGenerated() should be true")
+	}
+
+	if g, e := illust1.RestrictionAttributes, []string{"ai_generated"}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got Illusts[1].RestrictionAttributes %q, want %q", g, e)
+	}
+
+	if !illust1.User.IsAccessBlockingUser {
+		t.Errorf("Illusts[1].User.IsAccessBlockingUser should be true")
+	}
+
+	if g, e := ranking.NextURL, "https://app-api.pixiv.net/v1/illust/ranking?mode=day_r18&filter=for_android&offset=30"; g != e {
+		t.Errorf("got NextURL %q, want %q", g, e)
+	}
+}
+
 func TestClient_GetIllustRanking_NotFound(t *testing.T) {
 	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
 
@@ -263,17 +359,17 @@ func TestClient_GetIllustDetail(t *testing.T) {
 					ID:    1859785,
 					Title: "ヴァーン",
 					Type:  "illust",
-					ImageURLs: map[string]string{
-						"square_medium": "https://i.pximg.net/c/360x360_70/img-master/img/2008/10/14/00/34/39/1859785_p0_square1200.jpg",
-						"medium":        "https://i.pximg.net/c/540x540_70/img-master/img/2008/10/14/00/34/39/1859785_p0_master1200.jpg",
-						"large":         "https://i.pximg.net/c/600x1200_90/img-master/img/2008/10/14/00/34/39/1859785_p0_master1200.jpg",
+					ImageURLs: ImageURLs{
+						SquareMedium: "https://i.pximg.net/c/360x360_70/img-master/img/2008/10/14/00/34/39/1859785_p0_square1200.jpg",
+						Medium:       "https://i.pximg.net/c/540x540_70/img-master/img/2008/10/14/00/34/39/1859785_p0_master1200.jpg",
+						Large:        "https://i.pximg.net/c/600x1200_90/img-master/img/2008/10/14/00/34/39/1859785_p0_master1200.jpg",
 					},
 					Caption:  "一度は描いてみたくなります。アルパカ",
 					Restrict: 0,
 					User: GetIllustDetailIllustUser{
 						ID:      107576,
 						Name:    "のじゃ",
-						Account: "",
+						Account: "alice810",
 						ProfileImageURLs: map[string]string{
 							"medium": "https://i.pximg.net/user-profile/img/2009/04/21/22/41/44/704965_92aff81eafa0c49a6e5f11473e677e74_170.jpg",
 						},
@@ -320,16 +416,16 @@ func TestClient_GetIllustDetail(t *testing.T) {
 					ID:    62397682,
 					Title: "サーバルをさがせ！",
 					Type:  "illust",
-					ImageURLs: map[string]string{
-						"square_medium": "https://i.pximg.net/c/360x360_70/img-master/img/2017/04/14/08/28/03/62397682_p0_square1200.jpg",
-						"medium":        "https://i.pximg.net/c/540x540_70/img-master/img/2017/04/14/08/28/03/62397682_p0_master1200.jpg",
-						"large":         "https://i.pximg.net/c/600x1200_90/img-master/img/2017/04/14/08/28/03/62397682_p0_master1200.jpg"},
+					ImageURLs: ImageURLs{
+						SquareMedium: "https://i.pximg.net/c/360x360_70/img-master/img/2017/04/14/08/28/03/62397682_p0_square1200.jpg",
+						Medium:       "https://i.pximg.net/c/540x540_70/img-master/img/2017/04/14/08/28/03/62397682_p0_master1200.jpg",
+						Large:        "https://i.pximg.net/c/600x1200_90/img-master/img/2017/04/14/08/28/03/62397682_p0_master1200.jpg"},
 					Caption:  "我々の群れとしての強さを見せるのです",
 					Restrict: 0,
 					User: GetIllustDetailIllustUser{
 						ID:      1900912,
 						Name:    "アース桐下",
-						Account: "",
+						Account: "suna10",
 						ProfileImageURLs: map[string]string{
 							"medium": "https://i.pximg.net/user-profile/img/2017/08/22/20/22/32/13087631_87fc6cfbb6cfdc5d1879017d5e646860_170.png"},
 						IsFollowed: false,
@@ -356,19 +452,19 @@ func TestClient_GetIllustDetail(t *testing.T) {
 					MetaSinglePage: map[string]string{},
 					MetaPages: []GetIllustDetailIllustMetaPage{
 						{
-							ImageURLs: map[string]string{
-								"original":      "https://i.pximg.net/img-original/img/2017/04/14/08/28/03/62397682_p0.jpg",
-								"large":         "https://i.pximg.net/c/600x1200_90/img-master/img/2017/04/14/08/28/03/62397682_p0_master1200.jpg",
-								"medium":        "https://i.pximg.net/c/540x540_70/img-master/img/2017/04/14/08/28/03/62397682_p0_master1200.jpg",
-								"square_medium": "https://i.pximg.net/c/360x360_70/img-master/img/2017/04/14/08/28/03/62397682_p0_square1200.jpg",
+							ImageURLs: ImageURLs{
+								Original:     "https://i.pximg.net/img-original/img/2017/04/14/08/28/03/62397682_p0.jpg",
+								Large:        "https://i.pximg.net/c/600x1200_90/img-master/img/2017/04/14/08/28/03/62397682_p0_master1200.jpg",
+								Medium:       "https://i.pximg.net/c/540x540_70/img-master/img/2017/04/14/08/28/03/62397682_p0_master1200.jpg",
+								SquareMedium: "https://i.pximg.net/c/360x360_70/img-master/img/2017/04/14/08/28/03/62397682_p0_square1200.jpg",
 							},
 						},
 						{
-							ImageURLs: map[string]string{
-								"original":      "https://i.pximg.net/img-original/img/2017/04/14/08/28/03/62397682_p1.jpg",
-								"large":         "https://i.pximg.net/c/600x1200_90/img-master/img/2017/04/14/08/28/03/62397682_p1_master1200.jpg",
-								"medium":        "https://i.pximg.net/c/540x540_70/img-master/img/2017/04/14/08/28/03/62397682_p1_master1200.jpg",
-								"square_medium": "https://i.pximg.net/c/360x360_70/img-master/img/2017/04/14/08/28/03/62397682_p1_square1200.jpg",
+							ImageURLs: ImageURLs{
+								Original:     "https://i.pximg.net/img-original/img/2017/04/14/08/28/03/62397682_p1.jpg",
+								Large:        "https://i.pximg.net/c/600x1200_90/img-master/img/2017/04/14/08/28/03/62397682_p1_master1200.jpg",
+								Medium:       "https://i.pximg.net/c/540x540_70/img-master/img/2017/04/14/08/28/03/62397682_p1_master1200.jpg",
+								SquareMedium: "https://i.pximg.net/c/360x360_70/img-master/img/2017/04/14/08/28/03/62397682_p1_square1200.jpg",
 							},
 						},
 					},
@@ -382,6 +478,77 @@ func TestClient_GetIllustDetail(t *testing.T) {
 			},
 			body: fixture("fixtures/get_illust_detail_2.json"),
 		},
+		// 現行スキーマのフィールドを含むイラスト
+		{
+			illustID: 3,
+			illustDetail: &GetIllustDetail{
+				Illust: GetIllustDetailIllust{
+					ID:    105373621,
+					Title: "星降る夜に",
+					Type:  IllustTypeIllust,
+					ImageURLs: ImageURLs{
+						SquareMedium: "https://i.pximg.net/c/360x360_70/img-master/img/2023/02/14/00/00/12/105373621_p0_square1200.jpg",
+						Medium:       "https://i.pximg.net/c/540x540_70/img-master/img/2023/02/14/00/00/12/105373621_p0_master1200.jpg",
+						Large:        "https://i.pximg.net/c/600x1200_90/img-master/img/2023/02/14/00/00/12/105373621_p0_master1200.jpg",
+					},
+					Caption:  "バレンタインの夜",
+					Restrict: IllustRestrictPublic,
+					User: GetIllustDetailIllustUser{
+						ID:      10851340,
+						Name:    "藤ちょこ",
+						Account: "fuzichoco",
+						ProfileImageURLs: map[string]string{
+							"medium": "https://i.pximg.net/user-profile/img/2023/01/05/12/31/02/23889381_7b3e0b4e4a1b5d8c9e4f2a7d6c1b0a93_170.jpg",
+						},
+						IsFollowed:           false,
+						IsAccessBlockingUser: false,
+					},
+					Tags: []GetIllustDetailIllustTag{
+						{Name: "オリジナル", TranslatedName: stringPtr("original")},
+						{Name: "女の子", TranslatedName: stringPtr("girl")},
+						{Name: "星空", TranslatedName: stringPtr("starry sky")},
+						{Name: "オリジナル10000users入り"},
+					},
+					Tools:          []string{"CLIP STUDIO PAINT"},
+					CreateDate:     "2023-02-14T00:00:12+09:00",
+					PageCount:      2,
+					Width:          1600,
+					Height:         2263,
+					SanityLevel:    SanityLevelSemiBlack,
+					XRestrict:      XRestrictAllAges,
+					Series:         GetIllustDetailIllustSeries{ID: 172841, Title: "季節のイラスト"},
+					MetaSinglePage: map[string]string{},
+					MetaPages: []GetIllustDetailIllustMetaPage{
+						{
+							ImageURLs: ImageURLs{
+								SquareMedium: "https://i.pximg.net/c/360x360_70/img-master/img/2023/02/14/00/00/12/105373621_p0_square1200.jpg",
+								Medium:       "https://i.pximg.net/c/540x540_70/img-master/img/2023/02/14/00/00/12/105373621_p0_master1200.jpg",
+								Large:        "https://i.pximg.net/c/600x1200_90/img-master/img/2023/02/14/00/00/12/105373621_p0_master1200.jpg",
+								Original:     "https://i.pximg.net/img-original/img/2023/02/14/00/00/12/105373621_p0.png",
+							},
+						},
+						{
+							ImageURLs: ImageURLs{
+								SquareMedium: "https://i.pximg.net/c/360x360_70/img-master/img/2023/02/14/00/00/12/105373621_p1_square1200.jpg",
+								Medium:       "https://i.pximg.net/c/540x540_70/img-master/img/2023/02/14/00/00/12/105373621_p1_master1200.jpg",
+								Large:        "https://i.pximg.net/c/600x1200_90/img-master/img/2023/02/14/00/00/12/105373621_p1_master1200.jpg",
+								Original:     "https://i.pximg.net/img-original/img/2023/02/14/00/00/12/105373621_p1.png",
+							},
+						},
+					},
+					TotalView:             152203,
+					TotalBookmarks:        24871,
+					IsBookmarked:          false,
+					Visible:               true,
+					IsMuted:               false,
+					TotalComments:         87,
+					IllustAIType:          IllustAITypeNotAIGenerated,
+					IllustBookStyle:       0,
+					RestrictionAttributes: []string{},
+				},
+			},
+			body: fixture("fixtures/get_illust_detail_3.json"),
+		},
 	}
 
 	for _, c := range cases {
@@ -424,3 +591,329 @@ func TestClient_GetIllustDetail(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_SearchIllust(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if g, e := r.URL.Path, "/v1/search/illust"; g != e {
+			t.Errorf("got URL path %q, want %q", g, e)
+		}
+
+		if g, e := r.Method, http.MethodGet; g != e {
+			t.Errorf("got HTTP method %q, want %q", g, e)
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		expectedForm := url.Values{
+			"word":          []string{"オリジナル"},
+			"search_target": []string{"partial_match_for_tags"},
+			"sort":          []string{"date_desc"},
+			"start_date":    []string{"2017-09-01"},
+			"end_date":      []string{"2017-09-13"},
+			"filter":        []string{"for_android"},
+		}
+		if g, e := r.Form, expectedForm; !reflect.DeepEqual(g, e) {
+			t.Errorf("got form values %#v, want %#v", g, e)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture("fixtures/search_illust.json"))
+	}))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	search, err := cli.SearchIllust(
+		context.TODO(),
+		NewSearchIllustParams().
+			SetWord("オリジナル").
+			SetSearchTarget(SearchTargetPartialMatchForTags).
+			SetSort(SearchSortDateDesc).
+			SetStartDate("2017-09-01").
+			SetEndDate("2017-09-13"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := len(search.Illusts), 2; g != e {
+		t.Fatalf("got Illusts count %v, want %v", g, e)
+	}
+
+	expectedIllust01 := Illust{
+		ID:    64911803,
+		Title: "夕焼け",
+		Type:  "illust",
+		ImageURLs: ImageURLs{
+			SquareMedium: "https://i.pximg.net/c/360x360_70/img-master/img/2017/09/11/20/41/12/64911803_p0_square1200.jpg",
+			Medium:       "https://i.pximg.net/c/540x540_70/img-master/img/2017/09/11/20/41/12/64911803_p0_master1200.jpg",
+			Large:        "https://i.pximg.net/c/600x1200_90/img-master/img/2017/09/11/20/41/12/64911803_p0_master1200.jpg",
+		},
+		Caption:  "",
+		Restrict: 0,
+		User: IllustUser{
+			ID:      471355,
+			Name:    "しらび",
+			Account: "shirabi",
+			ProfileImageURLs: map[string]string{
+				"medium": "https://i.pximg.net/user-profile/img/2017/03/03/23/03/11/12226016_a54b0fd8e4d2c0e9c76cf96f6fcd5a47_170.jpg",
+			},
+			IsFollowed: false,
+		},
+		Tags: []IllustTag{
+			{Name: "オリジナル"},
+			{Name: "風景"},
+		},
+		Tools:          []string{"CLIP STUDIO PAINT"},
+		CreateDate:     "2017-09-11T20:41:12+09:00",
+		PageCount:      2,
+		Width:          2000,
+		Height:         1414,
+		SanityLevel:    2,
+		Series:         IllustSeries{ID: 0, Title: ""},
+		MetaSinglePage: map[string]string{},
+		MetaPages: []IllustMetaPage{
+			{
+				ImageURLs: ImageURLs{
+					SquareMedium: "https://i.pximg.net/c/360x360_70/img-master/img/2017/09/11/20/41/12/64911803_p0_square1200.jpg",
+					Medium:       "https://i.pximg.net/c/540x540_70/img-master/img/2017/09/11/20/41/12/64911803_p0_master1200.jpg",
+					Large:        "https://i.pximg.net/c/600x1200_90/img-master/img/2017/09/11/20/41/12/64911803_p0_master1200.jpg",
+					Original:     "https://i.pximg.net/img-original/img/2017/09/11/20/41/12/64911803_p0.png",
+				},
+			},
+			{
+				ImageURLs: ImageURLs{
+					SquareMedium: "https://i.pximg.net/c/360x360_70/img-master/img/2017/09/11/20/41/12/64911803_p1_square1200.jpg",
+					Medium:       "https://i.pximg.net/c/540x540_70/img-master/img/2017/09/11/20/41/12/64911803_p1_master1200.jpg",
+					Large:        "https://i.pximg.net/c/600x1200_90/img-master/img/2017/09/11/20/41/12/64911803_p1_master1200.jpg",
+					Original:     "https://i.pximg.net/img-original/img/2017/09/11/20/41/12/64911803_p1.png",
+				},
+			},
+		},
+		TotalView:      10293,
+		TotalBookmarks: 2241,
+		IsBookmarked:   false,
+		Visible:        true,
+		IsMuted:        false,
+	}
+	if g, e := search.Illusts[1], expectedIllust01; !reflect.DeepEqual(g, e) {
+		t.Errorf("got Illusts[1] %#v, want %#v", g, e)
+	}
+
+	if g, e := search.NextURL, "https://app-api.pixiv.net/v1/search/illust?word=%E3%82%AA%E3%83%AA%E3%82%B8%E3%83%8A%E3%83%AB&search_target=partial_match_for_tags&sort=date_desc&filter=for_android&offset=30"; g != e {
+		t.Errorf("got NextURL %q, want %q", g, e)
+	}
+
+	if g, e := search.SearchSpanLimit, 31536000; g != e {
+		t.Errorf("got SearchSpanLimit %v, want %v", g, e)
+	}
+}
+
+func TestClient_SearchIllustNext(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if g, e := r.URL.RequestURI(), "/v1/search/illust?filter=for_android&offset=30&search_target=exact_match_for_tags&word=foo"; g != e {
+			t.Errorf("got request URI %q, want %q", g, e)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture("fixtures/search_illust.json"))
+	}))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	search, err := cli.SearchIllustNext(context.TODO(), ts.URL+"/v1/search/illust?filter=for_android&offset=30&search_target=exact_match_for_tags&word=foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := len(search.Illusts), 2; g != e {
+		t.Errorf("got Illusts count %v, want %v", g, e)
+	}
+}
+
+func TestSearchIllustParams_Validate(t *testing.T) {
+	err := NewSearchIllustParams().SetWord("").Validate()
+	if err == nil {
+		t.Fatalf("Validate() should return an error if required fields are missing")
+	}
+
+	errParams, ok := err.(*ErrInvalidParams)
+	if !ok {
+		t.Fatalf("Validate() should return an *ErrInvalidParams")
+	}
+
+	expectedErrs := []ErrInvalidParam{
+		{Field: "Word", Message: "must not be empty"},
+		{Field: "SearchTarget", Message: "missing required field"},
+	}
+	if g, e := errParams.Errs, expectedErrs; !reflect.DeepEqual(g, e) {
+		t.Errorf("got errors %#v, want %#v", g, e)
+	}
+}
+
+type mockInvalidatableTokenProvider struct {
+	tokens      []string
+	invalidated []string
+}
+
+func (p *mockInvalidatableTokenProvider) Token(_ context.Context) (string, error) {
+	return p.tokens[len(p.invalidated)], nil
+}
+
+func (p *mockInvalidatableTokenProvider) InvalidateToken(token string) {
+	p.invalidated = append(p.invalidated, token)
+}
+
+func TestClient_Do_InvalidGrant(t *testing.T) {
+	tp := &mockInvalidatableTokenProvider{tokens: []string{"REVOKED_TOKEN", "FRESH_TOKEN"}}
+
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			cnt++
+		}()
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		if g, e := r.PostForm.Get("illust_id"), "64936066"; g != e {
+			t.Errorf("got illust_id %q, want %q", g, e)
+		}
+
+		switch cnt {
+		case 0:
+			if g, e := r.Header.Get("Authorization"), "Bearer REVOKED_TOKEN"; g != e {
+				t.Errorf("got Authorization header = %q, want %q", g, e)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(fixture("fixtures/api_error_invalid_grant.json"))
+		case 1:
+			if g, e := r.Header.Get("Authorization"), "Bearer FRESH_TOKEN"; g != e {
+				t.Errorf("got Authorization header = %q, want %q", g, e)
+			}
+		default:
+			t.Fatal("too many requests")
+		}
+	}))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader("illust_id=64936066"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := cli.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if g, e := res.StatusCode, http.StatusOK; g != e {
+		t.Errorf("got status code %d, want %d", g, e)
+	}
+
+	if g, e := tp.invalidated, []string{"REVOKED_TOKEN"}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got invalidated tokens %q, want %q", g, e)
+	}
+}
+
+func TestClient_GetIllustDetail_InvalidGrant(t *testing.T) {
+	tp := &mockTokenProvider{token: "REVOKED_TOKEN"}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(fixture("fixtures/api_error_invalid_grant.json"))
+	}))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	_, err := cli.GetIllustDetail(context.TODO(), NewGetIllustDetailParams().SetIllustID(1))
+
+	errAPI, ok := err.(ErrAPI)
+	if !ok {
+		t.Fatalf("GetIllustDetail() should return an ErrAPI if the token provider cannot invalidate tokens, got %#v", err)
+	}
+
+	if g, e := errAPI.Body.Error.Message, "Error occurred at the OAuth process. Please check your Access Token to fix this. Error Message: invalid_grant"; g != e {
+		t.Errorf("got error message %q, want %q", g, e)
+	}
+}
+
+func TestClient_GetUgoiraMetadata(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if g, e := r.URL.Path, "/v1/ugoira/metadata"; g != e {
+			t.Errorf("got URL path %q, want %q", g, e)
+		}
+
+		if g, e := r.Method, http.MethodGet; g != e {
+			t.Errorf("got HTTP method %q, want %q", g, e)
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		expectedForm := url.Values{"illust_id": []string{"64885306"}}
+		if g, e := r.Form, expectedForm; !reflect.DeepEqual(g, e) {
+			t.Errorf("got form values %#v, want %#v", g, e)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture("fixtures/get_ugoira_metadata.json"))
+	}))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	metadata, err := cli.GetUgoiraMetadata(context.TODO(), NewGetUgoiraMetadataParams().SetIllustID(64885306))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &GetUgoiraMetadata{
+		UgoiraMetadata: GetUgoiraMetadataUgoiraMetadata{
+			ZipURLs: map[string]string{
+				"medium": "https://i.pximg.net/img-zip-ugoira/img/2017/09/10/21/41/53/64885306_ugoira600x600.zip",
+			},
+			Frames: []GetUgoiraMetadataUgoiraMetadataFrame{
+				{File: "000000.jpg", Delay: 70},
+				{File: "000001.jpg", Delay: 70},
+				{File: "000002.jpg", Delay: 100},
+				{File: "000003.jpg", Delay: 250},
+			},
+		},
+	}
+	if g, e := metadata, expected; !reflect.DeepEqual(g, e) {
+		t.Errorf("got %#v, want %#v", g, e)
+	}
+}
+
+func illustIDs(illusts []Illust) []int {
+	ids := []int{}
+	for _, illust := range illusts {
+		ids = append(ids, illust.ID)
+	}
+	return ids
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,280 @@
+package pixiv
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+const (
+	RestrictPublic  = "public"
+	RestrictPrivate = "private"
+)
+
+const (
+	UserIllustsTypeIllust = "illust"
+	UserIllustsTypeManga  = "manga"
+)
+
+type GetUserDetailParams struct {
+	UserID *int
+	Filter *string
+}
+
+func NewGetUserDetailParams() *GetUserDetailParams {
+	return &GetUserDetailParams{}
+}
+
+func (p *GetUserDetailParams) SetUserID(userID int) *GetUserDetailParams {
+	p.UserID = &userID
+	return p
+}
+
+func (p *GetUserDetailParams) SetFilter(filter string) *GetUserDetailParams {
+	p.Filter = &filter
+	return p
+}
+
+func (p *GetUserDetailParams) Validate() error {
+	err := &ErrInvalidParams{}
+
+	if p.UserID == nil {
+		err.Add(ErrInvalidParam{"UserID", "missing required field"})
+	}
+
+	if err.Len() > 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (p *GetUserDetailParams) buildQuery() string {
+	v := url.Values{}
+
+	v.Set("user_id", strconv.Itoa(*p.UserID))
+
+	if p.Filter != nil {
+		v.Set("filter", *p.Filter)
+	} else {
+		v.Set("filter", "for_android")
+	}
+
+	return v.Encode()
+}
+
+func (c *Client) GetUserDetail(ctx context.Context, params *GetUserDetailParams) (*GetUserDetail, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result GetUserDetail
+
+	if err := c.get(ctx, c.baseURL()+"/v1/user/detail?"+params.buildQuery(), &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+type GetUserIllustsParams struct {
+	UserID *int
+	Type   *string
+	Offset *int
+	Filter *string
+}
+
+func NewGetUserIllustsParams() *GetUserIllustsParams {
+	return &GetUserIllustsParams{}
+}
+
+func (p *GetUserIllustsParams) SetUserID(userID int) *GetUserIllustsParams {
+	p.UserID = &userID
+	return p
+}
+
+func (p *GetUserIllustsParams) SetType(typ string) *GetUserIllustsParams {
+	p.Type = &typ
+	return p
+}
+
+func (p *GetUserIllustsParams) SetOffset(offset int) *GetUserIllustsParams {
+	p.Offset = &offset
+	return p
+}
+
+func (p *GetUserIllustsParams) SetFilter(filter string) *GetUserIllustsParams {
+	p.Filter = &filter
+	return p
+}
+
+func (p *GetUserIllustsParams) Validate() error {
+	err := &ErrInvalidParams{}
+
+	if p.UserID == nil {
+		err.Add(ErrInvalidParam{"UserID", "missing required field"})
+	}
+
+	if p.Type != nil && *p.Type != UserIllustsTypeIllust && *p.Type != UserIllustsTypeManga {
+		err.Add(ErrInvalidParam{"Type", "must be either \"illust\" or \"manga\""})
+	}
+
+	if err.Len() > 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (p *GetUserIllustsParams) buildQuery() string {
+	v := url.Values{}
+
+	v.Set("user_id", strconv.Itoa(*p.UserID))
+
+	if p.Type != nil {
+		v.Set("type", *p.Type)
+	}
+
+	if p.Offset != nil {
+		v.Set("offset", strconv.Itoa(*p.Offset))
+	}
+
+	if p.Filter != nil {
+		v.Set("filter", *p.Filter)
+	} else {
+		v.Set("filter", "for_android")
+	}
+
+	return v.Encode()
+}
+
+func (c *Client) GetUserIllusts(ctx context.Context, params *GetUserIllustsParams) (*GetUserIllusts, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result GetUserIllusts
+
+	if err := c.get(ctx, c.baseURL()+"/v1/user/illusts?"+params.buildQuery(), &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (c *Client) GetUserIllustsNext(ctx context.Context, nextURL string) (*GetUserIllusts, error) {
+	var result GetUserIllusts
+
+	if err := c.get(ctx, nextURL, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+type GetUserBookmarksIllustParams struct {
+	UserID        *int
+	Restrict      *string
+	MaxBookmarkID *int
+	Tag           *string
+	Filter        *string
+}
+
+func NewGetUserBookmarksIllustParams() *GetUserBookmarksIllustParams {
+	return &GetUserBookmarksIllustParams{}
+}
+
+func (p *GetUserBookmarksIllustParams) SetUserID(userID int) *GetUserBookmarksIllustParams {
+	p.UserID = &userID
+	return p
+}
+
+func (p *GetUserBookmarksIllustParams) SetRestrict(restrict string) *GetUserBookmarksIllustParams {
+	p.Restrict = &restrict
+	return p
+}
+
+func (p *GetUserBookmarksIllustParams) SetMaxBookmarkID(maxBookmarkID int) *GetUserBookmarksIllustParams {
+	p.MaxBookmarkID = &maxBookmarkID
+	return p
+}
+
+func (p *GetUserBookmarksIllustParams) SetTag(tag string) *GetUserBookmarksIllustParams {
+	p.Tag = &tag
+	return p
+}
+
+func (p *GetUserBookmarksIllustParams) SetFilter(filter string) *GetUserBookmarksIllustParams {
+	p.Filter = &filter
+	return p
+}
+
+func (p *GetUserBookmarksIllustParams) Validate() error {
+	err := &ErrInvalidParams{}
+
+	if p.UserID == nil {
+		err.Add(ErrInvalidParam{"UserID", "missing required field"})
+	}
+
+	if p.Restrict != nil && *p.Restrict != RestrictPublic && *p.Restrict != RestrictPrivate {
+		err.Add(ErrInvalidParam{"Restrict", "must be either \"public\" or \"private\""})
+	}
+
+	if err.Len() > 0 {
+		return err
+	}
+
+	return nil
+}
+
+func (p *GetUserBookmarksIllustParams) buildQuery() string {
+	v := url.Values{}
+
+	v.Set("user_id", strconv.Itoa(*p.UserID))
+
+	if p.Restrict != nil {
+		v.Set("restrict", *p.Restrict)
+	} else {
+		v.Set("restrict", RestrictPublic)
+	}
+
+	if p.MaxBookmarkID != nil {
+		v.Set("max_bookmark_id", strconv.Itoa(*p.MaxBookmarkID))
+	}
+
+	if p.Tag != nil {
+		v.Set("tag", *p.Tag)
+	}
+
+	if p.Filter != nil {
+		v.Set("filter", *p.Filter)
+	} else {
+		v.Set("filter", "for_android")
+	}
+
+	return v.Encode()
+}
+
+func (c *Client) GetUserBookmarksIllust(ctx context.Context, params *GetUserBookmarksIllustParams) (*GetUserBookmarksIllust, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result GetUserBookmarksIllust
+
+	if err := c.get(ctx, c.baseURL()+"/v1/user/bookmarks/illust?"+params.buildQuery(), &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (c *Client) GetUserBookmarksIllustNext(ctx context.Context, nextURL string) (*GetUserBookmarksIllust, error) {
+	var result GetUserBookmarksIllust
+
+	if err := c.get(ctx, nextURL, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
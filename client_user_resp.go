@@ -0,0 +1,78 @@
+package pixiv
+
+type GetUserDetail struct {
+	User             GetUserDetailUser             `json:"user"`
+	Profile          GetUserDetailProfile          `json:"profile"`
+	ProfilePublicity GetUserDetailProfilePublicity `json:"profile_publicity"`
+	Workspace        GetUserDetailWorkspace        `json:"workspace"`
+}
+
+type GetUserDetailUser struct {
+	ID               int               `json:"id"`
+	Name             string            `json:"name"`
+	Account          string            `json:"account"`
+	ProfileImageURLs map[string]string `json:"profile_image_urls"`
+	Comment          string            `json:"comment"`
+	IsFollowed       bool              `json:"is_followed"`
+}
+
+type GetUserDetailProfile struct {
+	Webpage                    string `json:"webpage"`
+	Gender                     string `json:"gender"`
+	Birth                      string `json:"birth"`
+	BirthDay                   string `json:"birth_day"`
+	BirthYear                  int    `json:"birth_year"`
+	Region                     string `json:"region"`
+	AddressID                  int    `json:"address_id"`
+	CountryCode                string `json:"country_code"`
+	Job                        string `json:"job"`
+	JobID                      int    `json:"job_id"`
+	TotalFollowUsers           int    `json:"total_follow_users"`
+	TotalMypixivUsers          int    `json:"total_mypixiv_users"`
+	TotalIllusts               int    `json:"total_illusts"`
+	TotalManga                 int    `json:"total_manga"`
+	TotalNovels                int    `json:"total_novels"`
+	TotalIllustBookmarksPublic int    `json:"total_illust_bookmarks_public"`
+	TotalIllustSeries          int    `json:"total_illust_series"`
+	BackgroundImageURL         string `json:"background_image_url"`
+	TwitterAccount             string `json:"twitter_account"`
+	TwitterURL                 string `json:"twitter_url"`
+	PawooURL                   string `json:"pawoo_url"`
+	IsPremium                  bool   `json:"is_premium"`
+	IsUsingCustomProfileImage  bool   `json:"is_using_custom_profile_image"`
+}
+
+type GetUserDetailProfilePublicity struct {
+	Gender    string `json:"gender"`
+	Region    string `json:"region"`
+	BirthDay  string `json:"birth_day"`
+	BirthYear string `json:"birth_year"`
+	Job       string `json:"job"`
+	Pawoo     bool   `json:"pawoo"`
+}
+
+type GetUserDetailWorkspace struct {
+	PC                string `json:"pc"`
+	Monitor           string `json:"monitor"`
+	Tool              string `json:"tool"`
+	Scanner           string `json:"scanner"`
+	Tablet            string `json:"tablet"`
+	Mouse             string `json:"mouse"`
+	Printer           string `json:"printer"`
+	Desktop           string `json:"desktop"`
+	Music             string `json:"music"`
+	Desk              string `json:"desk"`
+	Chair             string `json:"chair"`
+	Comment           string `json:"comment"`
+	WorkspaceImageURL string `json:"workspace_image_url"`
+}
+
+type GetUserIllusts struct {
+	Illusts []Illust `json:"illusts"`
+	NextURL string   `json:"next_url"`
+}
+
+type GetUserBookmarksIllust struct {
+	Illusts []Illust `json:"illusts"`
+	NextURL string   `json:"next_url"`
+}
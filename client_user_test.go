@@ -0,0 +1,328 @@
+package pixiv
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestClient_GetUserDetail(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if g, e := r.URL.Path, "/v1/user/detail"; g != e {
+			t.Errorf("got URL path %q, want %q", g, e)
+		}
+
+		if g, e := r.Method, http.MethodGet; g != e {
+			t.Errorf("got HTTP method %q, want %q", g, e)
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		expectedForm := url.Values{"user_id": []string{"471355"}, "filter": []string{"for_android"}}
+		if g, e := r.Form, expectedForm; !reflect.DeepEqual(g, e) {
+			t.Errorf("got form values %#v, want %#v", g, e)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture("fixtures/get_user_detail.json"))
+	}))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	detail, err := cli.GetUserDetail(context.TODO(), NewGetUserDetailParams().SetUserID(471355))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &GetUserDetail{
+		User: GetUserDetailUser{
+			ID:      471355,
+			Name:    "しらび",
+			Account: "shirabi",
+			ProfileImageURLs: map[string]string{
+				"medium": "https://i.pximg.net/user-profile/img/2017/03/03/23/03/11/12226016_a54b0fd8e4d2c0e9c76cf96f6fcd5a47_170.jpg",
+			},
+			Comment:    "イラストレーターです。\r\nお仕事のご依頼はメールにてお願いします。",
+			IsFollowed: false,
+		},
+		Profile: GetUserDetailProfile{
+			Webpage:                    "http://shirabi.example.com/",
+			Gender:                     "male",
+			Birth:                      "",
+			BirthDay:                   "07-13",
+			BirthYear:                  0,
+			Region:                     "日本 東京都",
+			AddressID:                  13,
+			CountryCode:                "",
+			Job:                        "イラストレーター",
+			JobID:                      4,
+			TotalFollowUsers:           152,
+			TotalMypixivUsers:          18,
+			TotalIllusts:               211,
+			TotalManga:                 12,
+			TotalNovels:                0,
+			TotalIllustBookmarksPublic: 1340,
+			TotalIllustSeries:          0,
+			BackgroundImageURL:         "https://i.pximg.net/c/1200x600_90_a2_g5/background/img/2016/08/27/01/00/00/471355_5f2a9c3e8a1d0a57b2c6bbd3d0a22b0f.jpg",
+			TwitterAccount:             "shirabii",
+			TwitterURL:                 "https://twitter.com/shirabii",
+			PawooURL:                   "https://pawoo.net/oauth_authentications/471355?provider=pixiv",
+			IsPremium:                  true,
+			IsUsingCustomProfileImage:  true,
+		},
+		ProfilePublicity: GetUserDetailProfilePublicity{
+			Gender:    "public",
+			Region:    "public",
+			BirthDay:  "public",
+			BirthYear: "private",
+			Job:       "public",
+			Pawoo:     true,
+		},
+		Workspace: GetUserDetailWorkspace{
+			PC:                "自作PC",
+			Monitor:           "EIZO ColorEdge CS2420",
+			Tool:              "CLIP STUDIO PAINT",
+			Tablet:            "Wacom Cintiq 22HD",
+			WorkspaceImageURL: "",
+		},
+	}
+	if g, e := detail, expected; !reflect.DeepEqual(g, e) {
+		t.Errorf("got %#v, want %#v", g, e)
+	}
+}
+
+func TestClient_GetUserIllusts(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if g, e := r.URL.Path, "/v1/user/illusts"; g != e {
+			t.Errorf("got URL path %q, want %q", g, e)
+		}
+
+		if g, e := r.Method, http.MethodGet; g != e {
+			t.Errorf("got HTTP method %q, want %q", g, e)
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		expectedForm := url.Values{
+			"user_id": []string{"471355"},
+			"type":    []string{"illust"},
+			"filter":  []string{"for_android"},
+		}
+		if g, e := r.Form, expectedForm; !reflect.DeepEqual(g, e) {
+			t.Errorf("got form values %#v, want %#v", g, e)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture("fixtures/get_user_illusts.json"))
+	}))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	illusts, err := cli.GetUserIllusts(
+		context.TODO(),
+		NewGetUserIllustsParams().SetUserID(471355).SetType(UserIllustsTypeIllust),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := len(illusts.Illusts), 2; g != e {
+		t.Fatalf("got Illusts count %v, want %v", g, e)
+	}
+
+	expectedIllust01 := Illust{
+		ID:    64537118,
+		Title: "雨上がり",
+		Type:  "illust",
+		ImageURLs: ImageURLs{
+			SquareMedium: "https://i.pximg.net/c/360x360_70/img-master/img/2017/08/20/00/00/31/64537118_p0_square1200.jpg",
+			Medium:       "https://i.pximg.net/c/540x540_70/img-master/img/2017/08/20/00/00/31/64537118_p0_master1200.jpg",
+			Large:        "https://i.pximg.net/c/600x1200_90/img-master/img/2017/08/20/00/00/31/64537118_p0_master1200.jpg",
+		},
+		Caption:  "",
+		Restrict: 0,
+		User: IllustUser{
+			ID:      471355,
+			Name:    "しらび",
+			Account: "shirabi",
+			ProfileImageURLs: map[string]string{
+				"medium": "https://i.pximg.net/user-profile/img/2017/03/03/23/03/11/12226016_a54b0fd8e4d2c0e9c76cf96f6fcd5a47_170.jpg",
+			},
+			IsFollowed: false,
+		},
+		Tags: []IllustTag{
+			{Name: "オリジナル"},
+			{Name: "女の子"},
+			{Name: "雨"},
+		},
+		Tools:       []string{"CLIP STUDIO PAINT"},
+		CreateDate:  "2017-08-20T00:00:31+09:00",
+		PageCount:   1,
+		Width:       1500,
+		Height:      2122,
+		SanityLevel: 2,
+		Series:      IllustSeries{ID: 0, Title: ""},
+		MetaSinglePage: map[string]string{
+			"original_image_url": "https://i.pximg.net/img-original/img/2017/08/20/00/00/31/64537118_p0.jpg",
+		},
+		MetaPages:      []IllustMetaPage{},
+		TotalView:      38402,
+		TotalBookmarks: 9120,
+		IsBookmarked:   false,
+		Visible:        true,
+		IsMuted:        false,
+	}
+	if g, e := illusts.Illusts[1], expectedIllust01; !reflect.DeepEqual(g, e) {
+		t.Errorf("got Illusts[1] %#v, want %#v", g, e)
+	}
+
+	if g, e := illusts.NextURL, "https://app-api.pixiv.net/v1/user/illusts?user_id=471355&type=illust&filter=for_android&offset=30"; g != e {
+		t.Errorf("got NextURL %q, want %q", g, e)
+	}
+}
+
+func TestClient_GetUserIllustsNext(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if g, e := r.URL.RequestURI(), "/v1/user/illusts?user_id=471355&type=illust&filter=for_android&offset=30"; g != e {
+			t.Errorf("got request URI %q, want %q", g, e)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture("fixtures/get_user_illusts.json"))
+	}))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	illusts, err := cli.GetUserIllustsNext(context.TODO(), ts.URL+"/v1/user/illusts?user_id=471355&type=illust&filter=for_android&offset=30")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := len(illusts.Illusts), 2; g != e {
+		t.Errorf("got Illusts count %v, want %v", g, e)
+	}
+}
+
+func TestClient_GetUserBookmarksIllust(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if g, e := r.URL.Path, "/v1/user/bookmarks/illust"; g != e {
+			t.Errorf("got URL path %q, want %q", g, e)
+		}
+
+		if g, e := r.Method, http.MethodGet; g != e {
+			t.Errorf("got HTTP method %q, want %q", g, e)
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		expectedForm := url.Values{
+			"user_id":         []string{"471355"},
+			"restrict":        []string{"private"},
+			"max_bookmark_id": []string{"1802716413"},
+			"tag":             []string{"ラブライブ!"},
+			"filter":          []string{"for_android"},
+		}
+		if g, e := r.Form, expectedForm; !reflect.DeepEqual(g, e) {
+			t.Errorf("got form values %#v, want %#v", g, e)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture("fixtures/get_user_bookmarks_illust.json"))
+	}))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	bookmarks, err := cli.GetUserBookmarksIllust(
+		context.TODO(),
+		NewGetUserBookmarksIllustParams().
+			SetUserID(471355).
+			SetRestrict(RestrictPrivate).
+			SetMaxBookmarkID(1802716413).
+			SetTag("ラブライブ!"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := len(bookmarks.Illusts), 1; g != e {
+		t.Fatalf("got Illusts count %v, want %v", g, e)
+	}
+
+	if g, e := bookmarks.Illusts[0].ID, 64936066; g != e {
+		t.Errorf("got Illusts[0].ID %v, want %v", g, e)
+	}
+
+	if g, e := bookmarks.Illusts[0].IsBookmarked, true; g != e {
+		t.Errorf("got Illusts[0].IsBookmarked %v, want %v", g, e)
+	}
+
+	if g, e := bookmarks.NextURL, "https://app-api.pixiv.net/v1/user/bookmarks/illust?user_id=471355&restrict=public&filter=for_android&max_bookmark_id=1802716412"; g != e {
+		t.Errorf("got NextURL %q, want %q", g, e)
+	}
+}
+
+func TestClient_GetUserBookmarksIllustNext(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if g, e := r.URL.RequestURI(), "/v1/user/bookmarks/illust?user_id=471355&restrict=public&filter=for_android&max_bookmark_id=1802716412"; g != e {
+			t.Errorf("got request URI %q, want %q", g, e)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture("fixtures/get_user_bookmarks_illust.json"))
+	}))
+	defer ts.Close()
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	bookmarks, err := cli.GetUserBookmarksIllustNext(context.TODO(), ts.URL+"/v1/user/bookmarks/illust?user_id=471355&restrict=public&filter=for_android&max_bookmark_id=1802716412")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := len(bookmarks.Illusts), 1; g != e {
+		t.Errorf("got Illusts count %v, want %v", g, e)
+	}
+}
+
+func TestGetUserBookmarksIllustParams_Validate(t *testing.T) {
+	err := NewGetUserBookmarksIllustParams().SetRestrict("all").Validate()
+	if err == nil {
+		t.Fatalf("Validate() should return an error if params are invalid")
+	}
+
+	errParams, ok := err.(*ErrInvalidParams)
+	if !ok {
+		t.Fatalf("Validate() should return an *ErrInvalidParams")
+	}
+
+	expectedErrs := []ErrInvalidParam{
+		{Field: "UserID", Message: "missing required field"},
+		{Field: "Restrict", Message: "must be either \"public\" or \"private\""},
+	}
+	if g, e := errParams.Errs, expectedErrs; !reflect.DeepEqual(g, e) {
+		t.Errorf("got errors %#v, want %#v", g, e)
+	}
+}
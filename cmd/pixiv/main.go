@@ -0,0 +1,343 @@
+// Command pixiv is a command-line client for the Pixiv app API.
+//
+// Credentials are read from the USERNAME, PASSWORD, CLIENT_ID and
+// CLIENT_SECRET environment variables, as in .env.example. REFRESH_TOKEN, if
+// set, is used instead of USERNAME and PASSWORD.
+//
+// Usage:
+//
+//	pixiv [-token-file path] <command> [flags] [args]
+//
+// Commands:
+//
+//	login                                      log in and print the refresh token
+//	ranking [-mode day] [-date 2017-09-01]     print a ranking
+//	detail <id>                                print an illust
+//	download <id> [-size original] [-o dir]    download every page of an illust
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/search2d/go-pixiv"
+)
+
+// errUsage is returned by commands whose arguments are invalid. The flag
+// package has already printed the reason.
+var errUsage = errors.New("usage error")
+
+type app struct {
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+
+	apiBaseURL   string
+	oauthBaseURL string
+	tokenFile    string
+}
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	go func() {
+		<-sig
+		cancel()
+	}()
+
+	a := &app{stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv}
+	os.Exit(a.run(ctx, os.Args[1:]))
+}
+
+func (a *app) run(ctx context.Context, args []string) int {
+	fs := flag.NewFlagSet("pixiv", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.StringVar(&a.tokenFile, "token-file", "", "save and reuse issued tokens in `path`")
+	fs.Usage = func() {
+		fmt.Fprintln(a.stderr, "usage: pixiv [-token-file path] <login|ranking|detail|download> [flags] [args]")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	commands := map[string]func(context.Context, []string) error{
+		"login":    a.login,
+		"ranking":  a.ranking,
+		"detail":   a.detail,
+		"download": a.download,
+	}
+
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(a.stderr, "pixiv: unknown command %q\n", fs.Arg(0))
+		fs.Usage()
+		return 2
+	}
+
+	if err := cmd(ctx, fs.Args()[1:]); err != nil {
+		if err == errUsage {
+			return 2
+		}
+		fmt.Fprintf(a.stderr, "pixiv: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+func (a *app) login(ctx context.Context, args []string) error {
+	fs := a.flagSet("login", "")
+	asJSON := fs.Bool("json", false, "print JSON")
+
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	tp := a.tokenProvider()
+	if _, err := tp.Token(ctx); err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return a.printJSON(map[string]string{"refresh_token": tp.RefreshToken()})
+	}
+
+	fmt.Fprintln(a.stdout, tp.RefreshToken())
+	return nil
+}
+
+func (a *app) ranking(ctx context.Context, args []string) error {
+	fs := a.flagSet("ranking", "")
+	mode := fs.String("mode", pixiv.RankingModeDay, "ranking `mode`, e.g. day, week, month, day_male")
+	date := fs.String("date", "", "ranking `date` in YYYY-MM-DD; defaults to the latest")
+	limit := fs.Int("limit", 0, "stop after at least `n` illusts; 0 fetches every page")
+	asJSON := fs.Bool("json", false, "print JSON Lines")
+
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	params := pixiv.NewGetIllustRankingParams().SetMode(*mode)
+	if *date != "" {
+		params.SetDate(*date)
+	}
+
+	cli := a.client()
+
+	pager := cli.NewPager(func(ctx context.Context) (pixiv.Page, error) {
+		return cli.GetIllustRanking(ctx, params)
+	}).SetMaxItems(*limit)
+
+	tw := tabwriter.NewWriter(a.stdout, 0, 8, 2, ' ', 0)
+	if !*asJSON {
+		fmt.Fprintln(tw, "RANK\tID\tTITLE\tUSER\tBOOKMARKS")
+	}
+
+	rank := 0
+	for pager.Next(ctx) {
+		for _, illust := range pager.Page().(*pixiv.GetIllustRanking).Illusts {
+			rank++
+
+			if *asJSON {
+				if err := a.printJSON(illust); err != nil {
+					return err
+				}
+				continue
+			}
+
+			fmt.Fprintf(tw, "%d\t%d\t%s\t%s\t%d\n", rank, illust.ID, illust.Title, illust.User.Name, illust.TotalBookmarks)
+		}
+	}
+
+	if err := pager.Err(); err != nil {
+		return err
+	}
+
+	return tw.Flush()
+}
+
+func (a *app) detail(ctx context.Context, args []string) error {
+	fs := a.flagSet("detail", " <id>")
+	asJSON := fs.Bool("json", false, "print JSON")
+
+	pos, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	id, err := a.parseID(fs, pos[0])
+	if err != nil {
+		return err
+	}
+
+	res, err := a.client().GetIllustDetail(ctx, pixiv.NewGetIllustDetailParams().SetIllustID(id))
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return a.printJSON(res.Illust)
+	}
+
+	illust := res.Illust
+
+	tags := ""
+	for i, tag := range illust.Tags {
+		if i > 0 {
+			tags += ", "
+		}
+		tags += tag.Name
+	}
+
+	tw := tabwriter.NewWriter(a.stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID\t%d\n", illust.ID)
+	fmt.Fprintf(tw, "Title\t%s\n", illust.Title)
+	fmt.Fprintf(tw, "Type\t%s\n", illust.Type)
+	fmt.Fprintf(tw, "User\t%s (%d)\n", illust.User.Name, illust.User.ID)
+	fmt.Fprintf(tw, "Created\t%s\n", illust.CreateDate)
+	fmt.Fprintf(tw, "Pages\t%d\n", illust.PageCount)
+	fmt.Fprintf(tw, "Size\t%dx%d\n", illust.Width, illust.Height)
+	fmt.Fprintf(tw, "Tags\t%s\n", tags)
+	fmt.Fprintf(tw, "Views\t%d\n", illust.TotalView)
+	fmt.Fprintf(tw, "Bookmarks\t%d\n", illust.TotalBookmarks)
+	fmt.Fprintf(tw, "Comments\t%d\n", illust.TotalComments)
+	return tw.Flush()
+}
+
+func (a *app) download(ctx context.Context, args []string) error {
+	fs := a.flagSet("download", " <id>")
+	size := fs.String("size", pixiv.ImageSizeOriginal, "image `size`: original, large, medium or square_medium")
+	dir := fs.String("o", ".", "output `dir`")
+
+	pos, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	id, err := a.parseID(fs, pos[0])
+	if err != nil {
+		return err
+	}
+
+	res, err := a.client().GetIllustDetail(ctx, pixiv.NewGetIllustDetailParams().SetIllustID(id))
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*dir, 0755); err != nil {
+		return err
+	}
+
+	d := &pixiv.Downloader{Dir: *dir}
+
+	names, err := d.DownloadIllust(ctx, res.Illust, *size)
+	for _, name := range names {
+		fmt.Fprintln(a.stdout, filepath.Join(*dir, name))
+	}
+	return err
+}
+
+func (a *app) tokenProvider() *pixiv.OauthTokenProvider {
+	tp := &pixiv.OauthTokenProvider{
+		BaseURL: a.oauthBaseURL,
+		Credential: pixiv.Credential{
+			Username:     a.getenv("USERNAME"),
+			Password:     a.getenv("PASSWORD"),
+			ClientID:     a.getenv("CLIENT_ID"),
+			ClientSecret: a.getenv("CLIENT_SECRET"),
+			RefreshToken: a.getenv("REFRESH_TOKEN"),
+		},
+	}
+
+	if a.tokenFile != "" {
+		tp.Store = &pixiv.FileTokenStore{Path: a.tokenFile}
+		tp.OnSaveError = func(err error) {
+			fmt.Fprintf(a.stderr, "pixiv: failed to save token: %v\n", err)
+		}
+		tp.OnLoadError = func(err error) {
+			fmt.Fprintf(a.stderr, "pixiv: ignoring saved token: %v\n", err)
+		}
+	}
+
+	return tp
+}
+
+func (a *app) client() *pixiv.Client {
+	return &pixiv.Client{
+		BaseURL:       a.apiBaseURL,
+		TokenProvider: a.tokenProvider(),
+		RetryPolicy:   &pixiv.RetryPolicy{},
+	}
+}
+
+func (a *app) flagSet(name string, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "usage: pixiv %s [flags]%s\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func (a *app) printJSON(v interface{}) error {
+	enc := json.NewEncoder(a.stdout)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+// parse parses flags that may appear before or after the positional
+// arguments, so that both `download -o dir 123` and `download 123 -o dir`
+// work, and checks that exactly n positional arguments were given.
+func parse(fs *flag.FlagSet, args []string, n int) ([]string, error) {
+	pos := []string{}
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, errUsage
+		}
+
+		if fs.NArg() == 0 {
+			break
+		}
+
+		pos = append(pos, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(pos) != n {
+		fs.Usage()
+		return nil, errUsage
+	}
+
+	return pos, nil
+}
+
+func (a *app) parseID(fs *flag.FlagSet, s string) (int, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil || id <= 0 {
+		fmt.Fprintf(a.stderr, "invalid illust ID %q\n", s)
+		fs.Usage()
+		return 0, errUsage
+	}
+	return id, nil
+}
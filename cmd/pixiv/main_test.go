@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/search2d/go-pixiv"
+	"github.com/search2d/go-pixiv/pixivtest"
+)
+
+func newTestApp(s *pixivtest.Server) (*app, *bytes.Buffer, *bytes.Buffer) {
+	env := map[string]string{
+		"USERNAME":      "USERNAME",
+		"PASSWORD":      "PASSWORD",
+		"CLIENT_ID":     "CLIENT_ID",
+		"CLIENT_SECRET": "CLIENT_SECRET",
+	}
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	a := &app{
+		stdout:       stdout,
+		stderr:       stderr,
+		getenv:       func(k string) string { return env[k] },
+		apiBaseURL:   s.URL,
+		oauthBaseURL: s.URL,
+	}
+
+	return a, stdout, stderr
+}
+
+func TestRun_Login(t *testing.T) {
+	s := pixivtest.NewServer()
+	defer s.Close()
+
+	a, stdout, stderr := newTestApp(s)
+
+	if g, e := a.run(context.TODO(), []string{"login"}), 0; g != e {
+		t.Fatalf("got exit code %d, want %d: %s", g, e, stderr)
+	}
+
+	if g, e := stdout.String(), "REFRESH_TOKEN_1\n"; g != e {
+		t.Errorf("got %q, want %q", g, e)
+	}
+}
+
+func TestRun_Ranking(t *testing.T) {
+	s := pixivtest.NewServer()
+	defer s.Close()
+
+	a, stdout, stderr := newTestApp(s)
+
+	if g, e := a.run(context.TODO(), []string{"ranking", "-mode", "week", "-date", "2017-09-01", "-json"}), 0; g != e {
+		t.Fatalf("got exit code %d, want %d: %s", g, e, stderr)
+	}
+
+	dec := json.NewDecoder(stdout)
+
+	ids := []int{}
+	for dec.More() {
+		var illust pixiv.Illust
+		if err := dec.Decode(&illust); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, illust.ID)
+	}
+
+	if g, e := len(ids), 45; g != e {
+		t.Errorf("got %d illusts, want %d", g, e)
+	}
+
+	for _, r := range s.Requests() {
+		if r.URL.Path != "/v1/illust/ranking" {
+			continue
+		}
+		if g, e := r.Form.Get("mode"), "week"; g != e {
+			t.Errorf("got mode %q, want %q", g, e)
+		}
+		if g, e := r.Form.Get("date"), "2017-09-01"; g != e {
+			t.Errorf("got date %q, want %q", g, e)
+		}
+	}
+}
+
+func TestRun_RankingTable(t *testing.T) {
+	s := pixivtest.NewServer()
+	defer s.Close()
+
+	a, stdout, stderr := newTestApp(s)
+
+	if g, e := a.run(context.TODO(), []string{"ranking", "-limit", "1"}), 0; g != e {
+		t.Fatalf("got exit code %d, want %d: %s", g, e, stderr)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+
+	if g, e := len(lines), 31; g != e {
+		t.Fatalf("got %d lines, want %d", g, e)
+	}
+
+	if g, e := strings.Fields(lines[0]), []string{"RANK", "ID", "TITLE", "USER", "BOOKMARKS"}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got header %q, want %q", g, e)
+	}
+
+	if g, e := strings.Fields(lines[1]), []string{"1", "1", "illust", "1", "user", "1", "0"}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got row %q, want %q", g, e)
+	}
+}
+
+func TestRun_Detail(t *testing.T) {
+	s := pixivtest.NewServer()
+	defer s.Close()
+
+	a, stdout, stderr := newTestApp(s)
+
+	if g, e := a.run(context.TODO(), []string{"detail", "3"}), 0; g != e {
+		t.Fatalf("got exit code %d, want %d: %s", g, e, stderr)
+	}
+
+	if !strings.Contains(stdout.String(), "illust 3") {
+		t.Errorf("got %q, which should contain the title", stdout)
+	}
+}
+
+func TestRun_Download(t *testing.T) {
+	s := pixivtest.NewServer()
+	defer s.Close()
+
+	s.Illusts = append(s.Illusts, s.NewIllust(100, 2))
+
+	dir, err := ioutil.TempDir("", "pixiv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	out := filepath.Join(dir, "out")
+
+	a, stdout, stderr := newTestApp(s)
+
+	if g, e := a.run(context.TODO(), []string{"download", "100", "-size", "large", "-o", out}), 0; g != e {
+		t.Fatalf("got exit code %d, want %d: %s", g, e, stderr)
+	}
+
+	expected := filepath.Join(out, "100_p0.jpg") + "\n" + filepath.Join(out, "100_p1.jpg") + "\n"
+	if g, e := stdout.String(), expected; g != e {
+		t.Errorf("got %q, want %q", g, e)
+	}
+
+	if _, err := os.Stat(filepath.Join(out, "100_p1.jpg")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	s := pixivtest.NewServer()
+	defer s.Close()
+
+	cases := [][]string{
+		{},
+		{"unknown"},
+		{"detail"},
+		{"detail", "abc"},
+		{"download", "1", "2"},
+		{"ranking", "-unknown"},
+	}
+
+	for _, args := range cases {
+		a, _, _ := newTestApp(s)
+
+		if g, e := a.run(context.TODO(), args), 2; g != e {
+			t.Errorf("%q: got exit code %d, want %d", args, g, e)
+		}
+	}
+}
+
+func TestRun_Error(t *testing.T) {
+	s := pixivtest.NewServer()
+	defer s.Close()
+
+	a, _, stderr := newTestApp(s)
+
+	if g, e := a.run(context.TODO(), []string{"detail", "999"}), 1; g != e {
+		t.Fatalf("got exit code %d, want %d", g, e)
+	}
+
+	if !strings.HasPrefix(stderr.String(), "pixiv: ") {
+		t.Errorf("got %q, which should report the error", stderr)
+	}
+}
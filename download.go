@@ -1,6 +1,18 @@
 package pixiv
 
-import "net/http"
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
 
 var DefaultDownloadHeaders = map[string]string{
 	"User-Agent":      "PixivAndroidApp/5.0.64 (Android 6.0; Google Nexus 5X - 6.0.0 - API 23 - 1080x1920)",
@@ -13,3 +25,359 @@ func SetDownloadHeaders(req *http.Request) {
 		req.Header.Set(k, v)
 	}
 }
+
+const (
+	ImageSizeOriginal     = "original"
+	ImageSizeLarge        = "large"
+	ImageSizeMedium       = "medium"
+	ImageSizeSquareMedium = "square_medium"
+)
+
+var DefaultFilenameTemplate = "{id}_p{page}.{ext}"
+
+// DownloadPage is the image of a single page of an illust.
+type DownloadPage struct {
+	IllustID int
+	Page     int
+	URL      string
+}
+
+// IllustPages resolves the image URL of every page of illust for size.
+// Multi-page illusts list all sizes in meta_pages, while single-page
+// illusts only have the original in meta_single_page and the other sizes in
+// image_urls.
+func IllustPages(illust Illust, size string) ([]DownloadPage, error) {
+	if len(illust.MetaPages) > 0 {
+		pages := make([]DownloadPage, len(illust.MetaPages))
+
+		for i, mp := range illust.MetaPages {
+			u, ok := mp.ImageURLs.Size(size)
+			if !ok {
+				return nil, fmt.Errorf("illust %d has no %q image for page %d", illust.ID, size, i)
+			}
+
+			pages[i] = DownloadPage{IllustID: illust.ID, Page: i, URL: u}
+		}
+
+		return pages, nil
+	}
+
+	var (
+		u  string
+		ok bool
+	)
+
+	if size == ImageSizeOriginal {
+		u, ok = illust.MetaSinglePage["original_image_url"]
+	} else {
+		u, ok = illust.ImageURLs.Size(size)
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("illust %d has no %q image", illust.ID, size)
+	}
+
+	return []DownloadPage{{IllustID: illust.ID, Page: 0, URL: u}}, nil
+}
+
+// Downloader downloads page images with DefaultDownloadHeaders. Each page is
+// written to the writer returned by Create, or to a file in Dir if Create is
+// nil. Files are named after Filename, in which {id}, {page} and {ext} are
+// replaced with the illust ID, the zero-based page number and the file
+// extension.
+type Downloader struct {
+	Client   *http.Client
+	Dir      string
+	Filename string
+	Create   func(name string) (io.WriteCloser, error)
+
+	// MaxAttempts is the number of attempts DownloadFile makes before
+	// giving up, resuming from where the previous attempt stopped.
+	MaxAttempts int
+}
+
+// Download downloads pages in order and returns the names they were
+// written to.
+func (d *Downloader) Download(ctx context.Context, pages []DownloadPage) ([]string, error) {
+	names := make([]string, 0, len(pages))
+
+	for _, page := range pages {
+		name, err := d.filename(page)
+		if err != nil {
+			return names, err
+		}
+
+		if err := d.download(ctx, page.URL, name); err != nil {
+			return names, err
+		}
+
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+func (d *Downloader) DownloadIllust(ctx context.Context, illust Illust, size string) ([]string, error) {
+	pages, err := IllustPages(illust, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.Download(ctx, pages)
+}
+
+func (d *Downloader) download(ctx context.Context, rawurl string, name string) error {
+	if d.Create == nil {
+		return d.DownloadFile(ctx, rawurl, filepath.Join(d.Dir, name))
+	}
+
+	req, err := http.NewRequest(http.MethodGet, rawurl, nil)
+	if err != nil {
+		return err
+	}
+
+	SetDownloadHeaders(req)
+
+	res, err := d.client().Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ErrDownload{URL: rawurl, StatusCode: res.StatusCode, Status: res.Status}
+	}
+
+	w, err := d.Create(name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(w, res.Body); err != nil {
+		w.Close()
+		return err
+	}
+
+	return w.Close()
+}
+
+// DownloadFile downloads rawurl to path. The body is written to path+".part"
+// and renamed to path only once it is complete. If the transfer is cut
+// short, the next attempt resumes with a Range request; a .part file left
+// over by an earlier call is resumed as well.
+//
+// Resumed requests carry the ETag or Last-Modified of the resource in an
+// If-Range header, so that a resource that changed in the meantime is
+// downloaded again from the start. The validator is kept next to the .part
+// file in a .part.validator file, and a .part file without one is discarded.
+func (d *Downloader) DownloadFile(ctx context.Context, rawurl string, path string) error {
+	part := &partialDownload{url: rawurl, path: path + ".part", total: -1}
+
+	validator, err := ioutil.ReadFile(part.validatorPath())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	part.validator = string(validator)
+
+	for attempt := 1; ; attempt++ {
+		retry, err := d.fetchPart(ctx, part)
+		if err == nil {
+			break
+		}
+
+		if !retry || attempt >= d.maxAttempts() || ctx.Err() != nil {
+			return err
+		}
+	}
+
+	if err := os.Rename(part.path, path); err != nil {
+		return err
+	}
+
+	if err := os.Remove(part.validatorPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+type partialDownload struct {
+	url       string
+	path      string
+	validator string
+	total     int64
+}
+
+func (p *partialDownload) validatorPath() string {
+	return p.path + ".validator"
+}
+
+// setValidator records the validator of a full response. Weak ETags cannot
+// be used in If-Range, so Last-Modified is used instead.
+func (p *partialDownload) setValidator(h http.Header) error {
+	p.validator = h.Get("ETag")
+	if p.validator == "" || strings.HasPrefix(p.validator, "W/") {
+		p.validator = h.Get("Last-Modified")
+	}
+
+	if p.validator == "" {
+		if err := os.Remove(p.validatorPath()); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	return ioutil.WriteFile(p.validatorPath(), []byte(p.validator), 0644)
+}
+
+// fetchPart requests the rest of the download and writes it to the .part
+// file. It reports whether a failure is worth retrying.
+func (d *Downloader) fetchPart(ctx context.Context, part *partialDownload) (bool, error) {
+	f, err := os.OpenFile(part.path, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	offset := fi.Size()
+
+	// Without a validator there is no telling whether the bytes already
+	// written belong to the current resource.
+	if part.validator == "" {
+		offset = 0
+	}
+
+	req, err := http.NewRequest(http.MethodGet, part.url, nil)
+	if err != nil {
+		return false, err
+	}
+
+	SetDownloadHeaders(req)
+
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		req.Header.Set("If-Range", part.validator)
+	}
+
+	res, err := d.client().Do(req.WithContext(ctx))
+	if err != nil {
+		return true, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		offset = 0
+		part.total = res.ContentLength
+		if err := part.setValidator(res.Header); err != nil {
+			return false, err
+		}
+	case http.StatusPartialContent:
+		start, total, ok := parseContentRange(res.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			if err := f.Truncate(0); err != nil {
+				return false, err
+			}
+			return true, fmt.Errorf("%s: unexpected Content-Range %q", part.url, res.Header.Get("Content-Range"))
+		}
+		part.total = total
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The .part file is longer than the resource; start over.
+		if err := f.Truncate(0); err != nil {
+			return false, err
+		}
+		return true, ErrDownload{URL: part.url, StatusCode: res.StatusCode, Status: res.Status}
+	default:
+		return res.StatusCode >= 500, ErrDownload{URL: part.url, StatusCode: res.StatusCode, Status: res.Status}
+	}
+
+	if err := f.Truncate(offset); err != nil {
+		return false, err
+	}
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return false, err
+	}
+
+	n, err := io.Copy(f, res.Body)
+	if err != nil {
+		return true, err
+	}
+
+	if part.total >= 0 && offset+n != part.total {
+		return true, fmt.Errorf("%s: got %d bytes, want %d", part.url, offset+n, part.total)
+	}
+
+	return false, f.Sync()
+}
+
+// parseContentRange parses a Content-Range header of the form
+// "bytes start-end/total". total is -1 if it is unknown.
+func parseContentRange(v string) (int64, int64, bool) {
+	if !strings.HasPrefix(v, "bytes ") {
+		return 0, 0, false
+	}
+
+	dash := strings.Index(v, "-")
+	slash := strings.Index(v, "/")
+	if dash < 0 || slash < dash {
+		return 0, 0, false
+	}
+
+	start, err := strconv.ParseInt(v[len("bytes "):dash], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	if v[slash+1:] == "*" {
+		return start, -1, true
+	}
+
+	total, err := strconv.ParseInt(v[slash+1:], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return start, total, true
+}
+
+func (d *Downloader) filename(page DownloadPage) (string, error) {
+	u, err := url.Parse(page.URL)
+	if err != nil {
+		return "", err
+	}
+
+	r := strings.NewReplacer(
+		"{id}", strconv.Itoa(page.IllustID),
+		"{page}", strconv.Itoa(page.Page),
+		"{ext}", strings.TrimPrefix(path.Ext(u.Path), "."),
+	)
+
+	return r.Replace(d.filenameTemplate()), nil
+}
+
+func (d *Downloader) client() *http.Client {
+	if d.Client == nil {
+		return http.DefaultClient
+	}
+	return d.Client
+}
+
+func (d *Downloader) maxAttempts() int {
+	if d.MaxAttempts == 0 {
+		return 3
+	}
+	return d.MaxAttempts
+}
+
+func (d *Downloader) filenameTemplate() string {
+	if d.Filename == "" {
+		return DefaultFilenameTemplate
+	}
+	return d.Filename
+}
@@ -1,8 +1,20 @@
 package pixiv
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestSetDownloadHeaders(t *testing.T) {
@@ -19,3 +31,505 @@ func TestSetDownloadHeaders(t *testing.T) {
 		}
 	}
 }
+
+func TestIllustPages(t *testing.T) {
+	single := GetIllustDetailIllust{
+		ID: 1859785,
+		ImageURLs: ImageURLs{
+			SquareMedium: "https://i.pximg.net/c/360x360_70/img-master/img/2008/10/14/00/34/39/1859785_p0_square1200.jpg",
+			Medium:       "https://i.pximg.net/c/540x540_70/img-master/img/2008/10/14/00/34/39/1859785_p0_master1200.jpg",
+			Large:        "https://i.pximg.net/c/600x1200_90/img-master/img/2008/10/14/00/34/39/1859785_p0_master1200.jpg",
+		},
+		MetaSinglePage: map[string]string{
+			"original_image_url": "https://i.pximg.net/img-original/img/2008/10/14/00/34/39/1859785_p0.jpg",
+		},
+		MetaPages: []GetIllustDetailIllustMetaPage{},
+	}
+
+	multi := GetIllustDetailIllust{
+		ID: 62397682,
+		ImageURLs: ImageURLs{
+			Large: "https://i.pximg.net/c/600x1200_90/img-master/img/2017/04/14/08/28/03/62397682_p0_master1200.jpg",
+		},
+		MetaSinglePage: map[string]string{},
+		MetaPages: []GetIllustDetailIllustMetaPage{
+			{
+				ImageURLs: ImageURLs{
+					Original: "https://i.pximg.net/img-original/img/2017/04/14/08/28/03/62397682_p0.jpg",
+					Large:    "https://i.pximg.net/c/600x1200_90/img-master/img/2017/04/14/08/28/03/62397682_p0_master1200.jpg",
+				},
+			},
+			{
+				ImageURLs: ImageURLs{
+					Original: "https://i.pximg.net/img-original/img/2017/04/14/08/28/03/62397682_p1.jpg",
+					Large:    "https://i.pximg.net/c/600x1200_90/img-master/img/2017/04/14/08/28/03/62397682_p1_master1200.jpg",
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		name   string
+		illust GetIllustDetailIllust
+		size   string
+		pages  []DownloadPage
+	}{
+		{
+			name:   "single_original",
+			illust: single,
+			size:   ImageSizeOriginal,
+			pages: []DownloadPage{
+				{IllustID: 1859785, Page: 0, URL: "https://i.pximg.net/img-original/img/2008/10/14/00/34/39/1859785_p0.jpg"},
+			},
+		},
+		{
+			name:   "single_square_medium",
+			illust: single,
+			size:   ImageSizeSquareMedium,
+			pages: []DownloadPage{
+				{IllustID: 1859785, Page: 0, URL: "https://i.pximg.net/c/360x360_70/img-master/img/2008/10/14/00/34/39/1859785_p0_square1200.jpg"},
+			},
+		},
+		{
+			name:   "multi_original",
+			illust: multi,
+			size:   ImageSizeOriginal,
+			pages: []DownloadPage{
+				{IllustID: 62397682, Page: 0, URL: "https://i.pximg.net/img-original/img/2017/04/14/08/28/03/62397682_p0.jpg"},
+				{IllustID: 62397682, Page: 1, URL: "https://i.pximg.net/img-original/img/2017/04/14/08/28/03/62397682_p1.jpg"},
+			},
+		},
+		{
+			name:   "multi_large",
+			illust: multi,
+			size:   ImageSizeLarge,
+			pages: []DownloadPage{
+				{IllustID: 62397682, Page: 0, URL: "https://i.pximg.net/c/600x1200_90/img-master/img/2017/04/14/08/28/03/62397682_p0_master1200.jpg"},
+				{IllustID: 62397682, Page: 1, URL: "https://i.pximg.net/c/600x1200_90/img-master/img/2017/04/14/08/28/03/62397682_p1_master1200.jpg"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pages, err := IllustPages(c.illust, c.size)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if g, e := pages, c.pages; !reflect.DeepEqual(g, e) {
+				t.Errorf("got %#v, want %#v", g, e)
+			}
+		})
+	}
+
+	if _, err := IllustPages(multi, ImageSizeMedium); err == nil {
+		t.Errorf("IllustPages() should return an error if the size is not available")
+	}
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestDownloader_Download(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for k, v := range DefaultDownloadHeaders {
+			if r.Header.Get(k) != v {
+				t.Errorf("got %s header %q, want %q", k, r.Header.Get(k), v)
+			}
+		}
+
+		if r.URL.Path == "/img-original/img/missing_p0.png" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer ts.Close()
+
+	pages := []DownloadPage{
+		{IllustID: 62397682, Page: 0, URL: ts.URL + "/img-original/img/2017/04/14/08/28/03/62397682_p0.jpg"},
+		{IllustID: 62397682, Page: 1, URL: ts.URL + "/img-original/img/2017/04/14/08/28/03/62397682_p1.png"},
+	}
+
+	t.Run("dir", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "go-pixiv")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		d := &Downloader{Dir: dir}
+
+		names, err := d.Download(context.TODO(), pages)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if g, e := names, []string{"62397682_p0.jpg", "62397682_p1.png"}; !reflect.DeepEqual(g, e) {
+			t.Errorf("got names %q, want %q", g, e)
+		}
+
+		buf, err := ioutil.ReadFile(filepath.Join(dir, "62397682_p1.png"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if g, e := string(buf), "/img-original/img/2017/04/14/08/28/03/62397682_p1.png"; g != e {
+			t.Errorf("got file content %q, want %q", g, e)
+		}
+	})
+
+	t.Run("create", func(t *testing.T) {
+		written := map[string]*bytes.Buffer{}
+
+		d := &Downloader{
+			Filename: "pixiv-{id}-{page}.{ext}",
+			Create: func(name string) (io.WriteCloser, error) {
+				written[name] = &bytes.Buffer{}
+				return nopWriteCloser{written[name]}, nil
+			},
+		}
+
+		if _, err := d.Download(context.TODO(), pages); err != nil {
+			t.Fatal(err)
+		}
+
+		if g, e := written["pixiv-62397682-0.jpg"].String(), "/img-original/img/2017/04/14/08/28/03/62397682_p0.jpg"; g != e {
+			t.Errorf("got content %q, want %q", g, e)
+		}
+
+		if g, e := len(written), 2; g != e {
+			t.Errorf("got %d files, want %d", g, e)
+		}
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		d := &Downloader{
+			Create: func(name string) (io.WriteCloser, error) {
+				return nopWriteCloser{ioutil.Discard}, nil
+			},
+		}
+
+		_, err := d.Download(context.TODO(), []DownloadPage{{IllustID: 1, URL: ts.URL + "/img-original/img/missing_p0.png"}})
+
+		errDownload, ok := err.(ErrDownload)
+		if !ok {
+			t.Fatalf("Download() should return an ErrDownload if 404 response is received, got %#v", err)
+		}
+
+		if g, e := errDownload.StatusCode, http.StatusNotFound; g != e {
+			t.Errorf("got StatusCode %v, want %v", g, e)
+		}
+	})
+}
+
+// cutConnection writes the headers and the first n bytes of body, then
+// closes the connection without finishing the response.
+func cutConnection(t *testing.T, w http.ResponseWriter, status int, header http.Header, body []byte, n int) {
+	conn, buf, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	fmt.Fprintf(buf, "HTTP/1.1 %d %s\r\n", status, http.StatusText(status))
+	header.Write(buf)
+	fmt.Fprintf(buf, "\r\n")
+	buf.Write(body[:n])
+	buf.Flush()
+}
+
+func TestDownloader_DownloadFile_Resume(t *testing.T) {
+	body := bytes.Repeat([]byte("0123456789"), 100)
+
+	var mx sync.Mutex
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mx.Lock()
+		n := cnt
+		cnt++
+		mx.Unlock()
+
+		switch n {
+		case 0:
+			if g, e := r.Header.Get("Range"), ""; g != e {
+				t.Errorf("got Range header %q, want %q", g, e)
+			}
+
+			header := http.Header{}
+			header.Set("Content-Length", strconv.Itoa(len(body)))
+			header.Set("ETag", `"5a1b2c"`)
+			cutConnection(t, w, http.StatusOK, header, body, 300)
+		case 1:
+			if g, e := r.Header.Get("Range"), "bytes=300-"; g != e {
+				t.Errorf("got Range header %q, want %q", g, e)
+			}
+
+			if g, e := r.Header.Get("If-Range"), `"5a1b2c"`; g != e {
+				t.Errorf("got If-Range header %q, want %q", g, e)
+			}
+
+			header := http.Header{}
+			header.Set("Content-Length", strconv.Itoa(len(body)-300))
+			header.Set("Content-Range", fmt.Sprintf("bytes 300-%d/%d", len(body)-1, len(body)))
+			cutConnection(t, w, http.StatusPartialContent, header, body[300:], 500)
+		case 2:
+			if g, e := r.Header.Get("Range"), "bytes=800-"; g != e {
+				t.Errorf("got Range header %q, want %q", g, e)
+			}
+
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes 800-%d/%d", len(body)-1, len(body)))
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(body[800:])
+		default:
+			t.Fatal("too many requests")
+		}
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "go-pixiv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "62397682_p0.jpg")
+
+	d := &Downloader{}
+
+	if err := d.DownloadFile(context.TODO(), ts.URL+"/img-original/img/62397682_p0.jpg", path); err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(buf, body) {
+		t.Errorf("got %d bytes, want %d bytes of the original body", len(buf), len(body))
+	}
+
+	if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+		t.Errorf("the .part file should be removed after completion, got %v", err)
+	}
+}
+
+func TestDownloader_DownloadFile_RangeIgnored(t *testing.T) {
+	body := bytes.Repeat([]byte("abcdefghij"), 50)
+
+	var mx sync.Mutex
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mx.Lock()
+		n := cnt
+		cnt++
+		mx.Unlock()
+
+		if n == 0 {
+			header := http.Header{}
+			header.Set("Content-Length", strconv.Itoa(len(body)))
+			header.Set("ETag", `"v1"`)
+			cutConnection(t, w, http.StatusOK, header, body, 120)
+			return
+		}
+
+		if g, e := r.Header.Get("Range"), "bytes=120-"; g != e {
+			t.Errorf("got Range header %q, want %q", g, e)
+		}
+		if g, e := r.Header.Get("If-Range"), `"v1"`; g != e {
+			t.Errorf("got If-Range header %q, want %q", g, e)
+		}
+
+		// The validator changed, so the Range header is ignored and the
+		// whole resource is sent again.
+		w.Header().Set("ETag", `"v2"`)
+		w.Write(body)
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "go-pixiv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "1859785_p0.jpg")
+
+	if err := (&Downloader{}).DownloadFile(context.TODO(), ts.URL, path); err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(buf, body) {
+		t.Errorf("got %d bytes, want %d bytes of the original body", len(buf), len(body))
+	}
+}
+
+func TestDownloader_DownloadFile_GiveUp(t *testing.T) {
+	body := bytes.Repeat([]byte("x"), 100)
+
+	var mx sync.Mutex
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mx.Lock()
+		cnt++
+		mx.Unlock()
+
+		header := http.Header{}
+		header.Set("Content-Length", strconv.Itoa(len(body)))
+		cutConnection(t, w, http.StatusOK, header, body, 10)
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "go-pixiv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "1_p0.jpg")
+
+	if err := (&Downloader{MaxAttempts: 2}).DownloadFile(context.TODO(), ts.URL, path); err == nil {
+		t.Fatalf("DownloadFile() should return an error if every attempt is cut short")
+	}
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	if g, e := cnt, 2; g != e {
+		t.Errorf("got %d requests, want %d", g, e)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("an incomplete download must not be renamed into place, got %v", err)
+	}
+
+	if _, err := os.Stat(path + ".part"); err != nil {
+		t.Errorf("the .part file should be kept for resuming, got %v", err)
+	}
+}
+
+func TestDownloader_DownloadFile_ResourceChanged(t *testing.T) {
+	body := bytes.Repeat([]byte("new-"), 25)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if g, e := r.Header.Get("Range"), "bytes=40-"; g != e {
+			t.Errorf("got Range header %q, want %q", g, e)
+		}
+
+		if g, e := r.Header.Get("If-Range"), `"old"`; g != e {
+			t.Errorf("got If-Range header %q, want %q", g, e)
+		}
+
+		// ServeContent ignores the Range header since If-Range does not
+		// match the current ETag.
+		w.Header().Set("ETag", `"new"`)
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(body))
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "go-pixiv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "1_p0.jpg")
+
+	// A .part file left by an earlier run against the old resource.
+	if err := ioutil.WriteFile(path+".part", bytes.Repeat([]byte("old-"), 10), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path+".part.validator", []byte(`"old"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := (&Downloader{}).DownloadFile(context.TODO(), ts.URL, path); err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(buf, body) {
+		t.Errorf("got %q, want %q", buf, body)
+	}
+
+	if _, err := os.Stat(path + ".part.validator"); !os.IsNotExist(err) {
+		t.Errorf("the .part.validator file should be removed after completion, got %v", err)
+	}
+}
+
+func TestDownloader_DownloadFile_PartWithoutValidator(t *testing.T) {
+	body := bytes.Repeat([]byte("new-"), 25)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if g, e := r.Header.Get("Range"), ""; g != e {
+			t.Errorf("got Range header %q, want %q", g, e)
+		}
+
+		w.Write(body)
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "go-pixiv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "1_p0.jpg")
+
+	if err := ioutil.WriteFile(path+".part", bytes.Repeat([]byte("old-"), 10), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := (&Downloader{}).DownloadFile(context.TODO(), ts.URL, path); err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(buf, body) {
+		t.Errorf("got %q, want %q", buf, body)
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	cases := []struct {
+		value string
+		start int64
+		total int64
+		ok    bool
+	}{
+		{value: "bytes 300-999/1000", start: 300, total: 1000, ok: true},
+		{value: "bytes 0-99/*", start: 0, total: -1, ok: true},
+		{value: "bytes */1000", start: 0, total: 0, ok: false},
+		{value: "300-999/1000", start: 0, total: 0, ok: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.value, func(t *testing.T) {
+			start, total, ok := parseContentRange(c.value)
+
+			if g, e := []interface{}{start, total, ok}, []interface{}{c.start, c.total, c.ok}; !reflect.DeepEqual(g, e) {
+				t.Errorf("got %v, want %v", g, e)
+			}
+		})
+	}
+}
@@ -46,3 +46,13 @@ type ErrInvalidParam struct {
 func (e ErrInvalidParam) Error() string {
 	return fmt.Sprintf("%s, %s", e.Field, e.Message)
 }
+
+type ErrDownload struct {
+	URL        string
+	StatusCode int
+	Status     string
+}
+
+func (e ErrDownload) Error() string {
+	return fmt.Sprintf("%s: %s", e.URL, e.Status)
+}
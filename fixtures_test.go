@@ -0,0 +1,175 @@
+package pixiv
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// fixtureTypes maps every fixture to the type it is decoded into.
+var fixtureTypes = map[string]interface{}{
+	"add_illust_comment.json":         AddIllustComment{},
+	"api_error.json":                  APIErrorBody{},
+	"api_error_invalid_grant.json":    APIErrorBody{},
+	"api_error_rate_limit.json":       APIErrorBody{},
+	"get_illust_bookmark_detail.json": GetIllustBookmarkDetail{},
+	"get_illust_comment_replies.json": GetIllustCommentReplies{},
+	"get_illust_comments.json":        GetIllustComments{},
+	"get_illust_detail_1.json":        GetIllustDetail{},
+	"get_illust_detail_2.json":        GetIllustDetail{},
+	"get_illust_detail_3.json":        GetIllustDetail{},
+	"get_illust_follow.json":          GetIllustFollow{},
+	"get_illust_new.json":             GetIllustNew{},
+	"get_illust_ranking.json":         GetIllustRanking{},
+	"get_illust_ranking_2.json":       GetIllustRanking{},
+	"get_illust_recommended.json":     GetIllustRecommended{},
+	"get_illust_related.json":         GetIllustRelated{},
+	"get_manga_recommended.json":      GetMangaRecommended{},
+	"get_ugoira_metadata.json":        GetUgoiraMetadata{},
+	"get_user_bookmarks_illust.json":  GetUserBookmarksIllust{},
+	"get_user_detail.json":            GetUserDetail{},
+	"get_user_follower.json":          GetUserFollower{},
+	"get_user_following.json":         GetUserFollowing{},
+	"get_user_illusts.json":           GetUserIllusts{},
+	"get_user_mypixiv.json":           GetUserMypixiv{},
+	"search_illust.json":              SearchIllust{},
+	"token_authorize.json":            Token{},
+	"token_error.json":                TokenErrorBody{},
+	"token_refresh.json":              Token{},
+}
+
+// TestFixtures_Strict fails when a fixture contains a field that the
+// corresponding response type does not declare, so that schema drift is
+// noticed when fixtures are refreshed from the live API.
+func TestFixtures_Strict(t *testing.T) {
+	paths, err := filepath.Glob("fixtures/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range paths {
+		name := filepath.Base(path)
+
+		t.Run(name, func(t *testing.T) {
+			v, ok := fixtureTypes[name]
+			if !ok {
+				t.Fatalf("no type is registered for the fixture in fixtureTypes")
+			}
+
+			unknown, err := unknownFields("", fixture(path), reflect.TypeOf(v))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(unknown) > 0 {
+				t.Errorf("got unknown fields %s", strings.Join(unknown, ", "))
+			}
+		})
+	}
+}
+
+// unknownFields returns the paths of the JSON object keys in data that have
+// no matching field in t. encoding/json gained DisallowUnknownFields only in
+// Go 1.10, hence the reflection.
+func unknownFields(path string, data json.RawMessage, t reflect.Type) ([]string, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	// Types that decode themselves, such as json.RawMessage, accept any
+	// field.
+	if string(data) == "null" || reflect.PtrTo(t).Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()) {
+		return nil, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+
+		fields := map[string]reflect.Type{}
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			if name != "" && name != "-" {
+				fields[name] = t.Field(i).Type
+			}
+		}
+
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		unknown := []string{}
+		for _, k := range keys {
+			ft, ok := fields[k]
+			if !ok {
+				unknown = append(unknown, path+"."+k)
+				continue
+			}
+
+			u, err := unknownFields(path+"."+k, obj[k], ft)
+			if err != nil {
+				return nil, err
+			}
+			unknown = append(unknown, u...)
+		}
+
+		return unknown, nil
+	case reflect.Slice:
+		var arr []json.RawMessage
+		if err := json.Unmarshal(data, &arr); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+
+		unknown := []string{}
+		for i, elem := range arr {
+			u, err := unknownFields(fmt.Sprintf("%s[%d]", path, i), elem, t.Elem())
+			if err != nil {
+				return nil, err
+			}
+			unknown = append(unknown, u...)
+		}
+
+		return unknown, nil
+	case reflect.Map:
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+
+		unknown := []string{}
+		for k, v := range obj {
+			u, err := unknownFields(path+"."+k, v, t.Elem())
+			if err != nil {
+				return nil, err
+			}
+			unknown = append(unknown, u...)
+		}
+
+		return unknown, nil
+	}
+
+	return nil, nil
+}
+
+func TestUnknownFields(t *testing.T) {
+	data := []byte(`{"illust":{"id":1,"foo":1,"user":{"id":1,"bar":true},"tags":[{"name":"a"},{"name":"b","baz":null}]}}`)
+
+	unknown, err := unknownFields("", data, reflect.TypeOf(GetIllustDetail{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{".illust.foo", ".illust.tags[1].baz", ".illust.user.bar"}
+	if g, e := unknown, expected; !reflect.DeepEqual(g, e) {
+		t.Errorf("got %q, want %q", g, e)
+	}
+}
@@ -0,0 +1,90 @@
+package pixiv
+
+import "time"
+
+type IllustType string
+
+const (
+	IllustTypeIllust IllustType = "illust"
+	IllustTypeManga  IllustType = "manga"
+	IllustTypeUgoira IllustType = "ugoira"
+)
+
+type IllustRestrict int
+
+const (
+	IllustRestrictPublic  IllustRestrict = 0
+	IllustRestrictMypixiv IllustRestrict = 1
+	IllustRestrictPrivate IllustRestrict = 2
+)
+
+type SanityLevel int
+
+const (
+	SanityLevelUnchecked SanityLevel = 0
+	SanityLevelWhite     SanityLevel = 2
+	SanityLevelSemiBlack SanityLevel = 4
+	SanityLevelBlack     SanityLevel = 6
+)
+
+type XRestrict int
+
+const (
+	XRestrictAllAges XRestrict = 0
+	XRestrictR18     XRestrict = 1
+	XRestrictR18G    XRestrict = 2
+)
+
+type IllustAIType int
+
+const (
+	IllustAITypeUnknown        IllustAIType = 0
+	IllustAITypeNotAIGenerated IllustAIType = 1
+	IllustAITypeAIGenerated    IllustAIType = 2
+)
+
+// ImageURLs holds the URLs of an image in each size. Original is only set
+// for the pages of multi-page illusts; see Illust.MetaSinglePage otherwise.
+type ImageURLs struct {
+	SquareMedium string `json:"square_medium,omitempty"`
+	Medium       string `json:"medium,omitempty"`
+	Large        string `json:"large,omitempty"`
+	Original     string `json:"original,omitempty"`
+}
+
+// Size returns the URL for one of the ImageSize* constants.
+func (u ImageURLs) Size(size string) (string, bool) {
+	var s string
+
+	switch size {
+	case ImageSizeSquareMedium:
+		s = u.SquareMedium
+	case ImageSizeMedium:
+		s = u.Medium
+	case ImageSizeLarge:
+		s = u.Large
+	case ImageSizeOriginal:
+		s = u.Original
+	}
+
+	return s, s != ""
+}
+
+// CreatedAt parses CreateDate, which is in RFC 3339 format with a +09:00
+// offset.
+func (i Illust) CreatedAt() (time.Time, error) {
+	return time.Parse(time.RFC3339, i.CreateDate)
+}
+
+// IsR18 reports whether the illust is restricted to adults, including R-18G.
+func (i Illust) IsR18() bool {
+	return i.XRestrict >= XRestrictR18
+}
+
+func (i Illust) IsR18G() bool {
+	return i.XRestrict == XRestrictR18G
+}
+
+func (i Illust) IsAIGenerated() bool {
+	return i.IllustAIType == IllustAITypeAIGenerated
+}
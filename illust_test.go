@@ -0,0 +1,130 @@
+package pixiv
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIllust_CreatedAt(t *testing.T) {
+	illust := Illust{CreateDate: "2017-09-13T12:30:00+09:00"}
+
+	createdAt, err := illust.CreatedAt()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := createdAt, time.Date(2017, 9, 13, 3, 30, 0, 0, time.UTC); !g.Equal(e) {
+		t.Errorf("got %v, want %v", g, e)
+	}
+
+	if _, offset := createdAt.Zone(); offset != 9*60*60 {
+		t.Errorf("got zone offset %d, want %d", offset, 9*60*60)
+	}
+}
+
+func TestIllust_IsR18(t *testing.T) {
+	cases := []struct {
+		xRestrict XRestrict
+		r18       bool
+		r18g      bool
+	}{
+		{xRestrict: XRestrictAllAges, r18: false, r18g: false},
+		{xRestrict: XRestrictR18, r18: true, r18g: false},
+		{xRestrict: XRestrictR18G, r18: true, r18g: true},
+	}
+
+	for _, c := range cases {
+		illust := Illust{XRestrict: c.xRestrict}
+
+		if g, e := illust.IsR18(), c.r18; g != e {
+			t.Errorf("got IsR18() %v for x_restrict %d, want %v", g, c.xRestrict, e)
+		}
+
+		if g, e := illust.IsR18G(), c.r18g; g != e {
+			t.Errorf("got IsR18G() %v for x_restrict %d, want %v", g, c.xRestrict, e)
+		}
+	}
+}
+
+func TestImageURLs_Size(t *testing.T) {
+	urls := ImageURLs{
+		SquareMedium: "https://i.pximg.net/c/360x360_70/img-master/img/2017/04/14/08/28/03/62397682_p0_square1200.jpg",
+		Medium:       "https://i.pximg.net/c/540x540_70/img-master/img/2017/04/14/08/28/03/62397682_p0_master1200.jpg",
+		Large:        "https://i.pximg.net/c/600x1200_90/img-master/img/2017/04/14/08/28/03/62397682_p0_master1200.jpg",
+	}
+
+	if g, ok := urls.Size(ImageSizeMedium); !ok || g != urls.Medium {
+		t.Errorf("got %q, %v, want %q, true", g, ok, urls.Medium)
+	}
+
+	if _, ok := urls.Size(ImageSizeOriginal); ok {
+		t.Errorf("Size() should report false if the size is not set")
+	}
+
+	if _, ok := urls.Size("px_128x128"); ok {
+		t.Errorf("Size() should report false for an unknown size")
+	}
+}
+
+func TestIllust_JSONRoundTrip(t *testing.T) {
+	var raw struct {
+		Illust map[string]json.RawMessage `json:"illust"`
+	}
+
+	if err := json.Unmarshal(fixture("fixtures/get_illust_detail_2.json"), &raw); err != nil {
+		t.Fatal(err)
+	}
+
+	var detail GetIllustDetail
+
+	if err := json.Unmarshal(fixture("fixtures/get_illust_detail_2.json"), &detail); err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := detail.Illust.Type, IllustTypeIllust; g != e {
+		t.Errorf("got Type %q, want %q", g, e)
+	}
+
+	if g, e := detail.Illust.SanityLevel, SanityLevelSemiBlack; g != e {
+		t.Errorf("got SanityLevel %d, want %d", g, e)
+	}
+
+	buf, err := json.Marshal(detail.Illust)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Illust
+
+	if err := json.Unmarshal(buf, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := decoded, detail.Illust; !reflect.DeepEqual(g, e) {
+		t.Errorf("got %#v, want %#v", g, e)
+	}
+
+	var encoded map[string]json.RawMessage
+
+	if err := json.Unmarshal(buf, &encoded); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"type", "image_urls", "restrict", "sanity_level", "create_date"} {
+		var g, e interface{}
+
+		if err := json.Unmarshal(encoded[key], &g); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := json.Unmarshal(raw.Illust[key], &e); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(g, e) {
+			t.Errorf("got %s %#v, want %#v", key, g, e)
+		}
+	}
+}
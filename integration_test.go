@@ -27,31 +27,19 @@ func TestIntegration_GetIllustRanking(t *testing.T) {
 
 	illusts := []GetIllustRankingIllust{}
 
-	initial, err := cli.GetIllustRanking(
-		ctx,
-		NewGetIllustRankingParams().SetMode(RankingModeDay).SetDate("2017-09-01"),
-	)
-	if err != nil {
-		t.Fatal(err)
+	pager := cli.NewPager(func(ctx context.Context) (Page, error) {
+		return cli.GetIllustRanking(
+			ctx,
+			NewGetIllustRankingParams().SetMode(RankingModeDay).SetDate("2017-09-01"),
+		)
+	})
+
+	for pager.Next(ctx) {
+		illusts = append(illusts, pager.Page().(*GetIllustRanking).Illusts...)
 	}
 
-	illusts = append(illusts, initial.Illusts...)
-
-	next := initial.NextURL
-
-	for {
-		if len(next) == 0 {
-			break
-		}
-
-		ranking, err := cli.GetIllustRankingNext(ctx, next)
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		illusts = append(illusts, ranking.Illusts...)
-
-		next = ranking.NextURL
+	if err := pager.Err(); err != nil {
+		t.Fatal(err)
 	}
 
 	for offset, illust := range illusts {
@@ -0,0 +1,201 @@
+package pixiv
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Page is a list response that can be continued by following its next_url.
+// Pages must be pointers to the response structs.
+type Page interface {
+	NextPageURL() string
+	Len() int
+}
+
+// IllustPage is a Page of illusts.
+type IllustPage interface {
+	Page
+	PageIllusts() []Illust
+}
+
+// PageFunc fetches the first page of a list endpoint.
+type PageFunc func(ctx context.Context) (Page, error)
+
+// Pager iterates over the pages of a list endpoint by following next_url.
+// The following pages are decoded into the same type as the first page.
+//
+//	pager := cli.NewPager(func(ctx context.Context) (pixiv.Page, error) {
+//		return cli.GetIllustRanking(ctx, params)
+//	})
+//	for pager.Next(ctx) {
+//		ranking := pager.Page().(*pixiv.GetIllustRanking)
+//		...
+//	}
+//	if err := pager.Err(); err != nil {
+//		...
+//	}
+type Pager struct {
+	client *Client
+	first  PageFunc
+
+	maxPages int
+	maxItems int
+	while    func(Page) bool
+
+	page  Page
+	next  string
+	pages int
+	items int
+	done  bool
+	err   error
+}
+
+func (c *Client) NewPager(first PageFunc) *Pager {
+	return &Pager{client: c, first: first}
+}
+
+// SetMaxPages limits the number of pages fetched. Zero means no limit.
+func (p *Pager) SetMaxPages(maxPages int) *Pager {
+	p.maxPages = maxPages
+	return p
+}
+
+// SetMaxItems stops fetching once at least maxItems items have been returned.
+// The last page is not truncated. Zero means no limit.
+func (p *Pager) SetMaxItems(maxItems int) *Pager {
+	p.maxItems = maxItems
+	return p
+}
+
+// SetWhile sets a predicate called with every fetched page. The page is
+// still returned, but no further pages are fetched once it returns false.
+func (p *Pager) SetWhile(while func(Page) bool) *Pager {
+	p.while = while
+	return p
+}
+
+func (p *Pager) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+
+	if (p.maxPages > 0 && p.pages >= p.maxPages) || (p.maxItems > 0 && p.items >= p.maxItems) {
+		p.done = true
+		return false
+	}
+
+	var (
+		page Page
+		err  error
+	)
+
+	if p.page == nil {
+		page, err = p.first(ctx)
+	} else {
+		page, err = p.fetchNext(ctx)
+	}
+	if err != nil {
+		p.err = err
+		p.done = true
+		return false
+	}
+
+	if page == nil {
+		p.err = fmt.Errorf("PageFunc returned a nil page")
+		p.done = true
+		return false
+	}
+
+	// The following pages are decoded into a new value of the pointed-to
+	// type, so the page must be a pointer.
+	if v := reflect.ValueOf(page); v.Kind() != reflect.Ptr {
+		p.err = fmt.Errorf("PageFunc returned a %T, which is not a pointer", page)
+		p.done = true
+		return false
+	} else if v.IsNil() {
+		p.err = fmt.Errorf("PageFunc returned a nil page")
+		p.done = true
+		return false
+	}
+
+	p.page = page
+	p.next = page.NextPageURL()
+	p.pages++
+	p.items += page.Len()
+
+	if p.next == "" || (p.while != nil && !p.while(page)) {
+		p.done = true
+	}
+
+	return true
+}
+
+func (p *Pager) Page() Page {
+	return p.page
+}
+
+func (p *Pager) Err() error {
+	return p.err
+}
+
+func (p *Pager) fetchNext(ctx context.Context) (Page, error) {
+	page := reflect.New(reflect.TypeOf(p.page).Elem()).Interface().(Page)
+
+	if err := p.client.get(ctx, p.next, page); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+func (r *GetIllustRanking) NextPageURL() string   { return r.NextURL }
+func (r *GetIllustRanking) Len() int              { return len(r.Illusts) }
+func (r *GetIllustRanking) PageIllusts() []Illust { return r.Illusts }
+
+func (r *SearchIllust) NextPageURL() string   { return r.NextURL }
+func (r *SearchIllust) Len() int              { return len(r.Illusts) }
+func (r *SearchIllust) PageIllusts() []Illust { return r.Illusts }
+
+func (r *GetIllustRelated) NextPageURL() string   { return r.NextURL }
+func (r *GetIllustRelated) Len() int              { return len(r.Illusts) }
+func (r *GetIllustRelated) PageIllusts() []Illust { return r.Illusts }
+
+func (r *GetIllustRecommended) NextPageURL() string   { return r.NextURL }
+func (r *GetIllustRecommended) Len() int              { return len(r.Illusts) }
+func (r *GetIllustRecommended) PageIllusts() []Illust { return r.Illusts }
+
+func (r *GetMangaRecommended) NextPageURL() string   { return r.NextURL }
+func (r *GetMangaRecommended) Len() int              { return len(r.Illusts) }
+func (r *GetMangaRecommended) PageIllusts() []Illust { return r.Illusts }
+
+func (r *GetIllustFollow) NextPageURL() string   { return r.NextURL }
+func (r *GetIllustFollow) Len() int              { return len(r.Illusts) }
+func (r *GetIllustFollow) PageIllusts() []Illust { return r.Illusts }
+
+func (r *GetIllustNew) NextPageURL() string   { return r.NextURL }
+func (r *GetIllustNew) Len() int              { return len(r.Illusts) }
+func (r *GetIllustNew) PageIllusts() []Illust { return r.Illusts }
+
+func (r *GetIllustComments) NextPageURL() string { return r.NextURL }
+func (r *GetIllustComments) Len() int            { return len(r.Comments) }
+
+func (r *GetIllustCommentReplies) NextPageURL() string { return r.NextURL }
+func (r *GetIllustCommentReplies) Len() int            { return len(r.Comments) }
+
+func (r *GetUserIllusts) NextPageURL() string   { return r.NextURL }
+func (r *GetUserIllusts) Len() int              { return len(r.Illusts) }
+func (r *GetUserIllusts) PageIllusts() []Illust { return r.Illusts }
+
+func (r *GetUserBookmarksIllust) NextPageURL() string   { return r.NextURL }
+func (r *GetUserBookmarksIllust) Len() int              { return len(r.Illusts) }
+func (r *GetUserBookmarksIllust) PageIllusts() []Illust { return r.Illusts }
+
+func (r *GetUserFollowing) NextPageURL() string { return r.NextURL }
+func (r *GetUserFollowing) Len() int            { return len(r.UserPreviews) }
+
+func (r *GetUserFollower) NextPageURL() string { return r.NextURL }
+func (r *GetUserFollower) Len() int            { return len(r.UserPreviews) }
+
+func (r *GetUserMypixiv) NextPageURL() string { return r.NextURL }
+func (r *GetUserMypixiv) Len() int            { return len(r.UserPreviews) }
@@ -0,0 +1,177 @@
+package pixiv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func newRankingPagesServer(t *testing.T, numPages, pageSize int) *httptest.Server {
+	var ts *httptest.Server
+
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset := 0
+		if s := r.URL.Query().Get("offset"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			offset = n
+		}
+
+		ranking := GetIllustRanking{}
+		for i := 0; i < pageSize; i++ {
+			ranking.Illusts = append(ranking.Illusts, GetIllustRankingIllust{ID: offset + i + 1})
+		}
+		if offset+pageSize < numPages*pageSize {
+			ranking.NextURL = fmt.Sprintf("%s/v1/illust/ranking?mode=day&offset=%d", ts.URL, offset+pageSize)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ranking)
+	}))
+
+	return ts
+}
+
+func collectRankingIDs(t *testing.T, pager *Pager) []int {
+	ids := []int{}
+
+	for pager.Next(context.TODO()) {
+		ranking, ok := pager.Page().(*GetIllustRanking)
+		if !ok {
+			t.Fatalf("got page %T, want *GetIllustRanking", pager.Page())
+		}
+
+		for _, illust := range ranking.Illusts {
+			ids = append(ids, illust.ID)
+		}
+	}
+
+	if err := pager.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return ids
+}
+
+func TestPager(t *testing.T) {
+	ts := newRankingPagesServer(t, 3, 2)
+	defer ts.Close()
+
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	newPager := func() *Pager {
+		return cli.NewPager(func(ctx context.Context) (Page, error) {
+			return cli.GetIllustRanking(ctx, NewGetIllustRankingParams().SetMode(RankingModeDay))
+		})
+	}
+
+	cases := []struct {
+		name  string
+		pager *Pager
+		ids   []int
+	}{
+		{
+			name:  "all",
+			pager: newPager(),
+			ids:   []int{1, 2, 3, 4, 5, 6},
+		},
+		{
+			name:  "max_pages",
+			pager: newPager().SetMaxPages(2),
+			ids:   []int{1, 2, 3, 4},
+		},
+		{
+			name:  "max_items",
+			pager: newPager().SetMaxItems(3),
+			ids:   []int{1, 2, 3, 4},
+		},
+		{
+			name: "while",
+			pager: newPager().SetWhile(func(page Page) bool {
+				return page.(*GetIllustRanking).Illusts[0].ID < 3
+			}),
+			ids: []int{1, 2, 3, 4},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if g, e := collectRankingIDs(t, c.pager), c.ids; !reflect.DeepEqual(g, e) {
+				t.Errorf("got IDs %v, want %v", g, e)
+			}
+		})
+	}
+}
+
+func TestPager_Error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "" {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"illusts":[{"id":1}],"next_url":"http://%s/v1/illust/ranking?mode=day&offset=1"}`, r.Host)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write(fixture("fixtures/api_error.json"))
+	}))
+	defer ts.Close()
+
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL}
+
+	pager := cli.NewPager(func(ctx context.Context) (Page, error) {
+		return cli.GetIllustRanking(ctx, NewGetIllustRankingParams().SetMode(RankingModeDay))
+	})
+
+	if g, e := pager.Next(context.TODO()), true; g != e {
+		t.Fatalf("got Next() %v, want %v", g, e)
+	}
+
+	if g, e := pager.Next(context.TODO()), false; g != e {
+		t.Fatalf("got Next() %v, want %v", g, e)
+	}
+
+	errAPI, ok := pager.Err().(ErrAPI)
+	if !ok {
+		t.Fatalf("Err() should return an ErrAPI if 404 response is received, got %#v", pager.Err())
+	}
+
+	if g, e := errAPI.StatusCode, http.StatusNotFound; g != e {
+		t.Errorf("got StatusCode %v, want %v", g, e)
+	}
+
+	if g, e := pager.Next(context.TODO()), false; g != e {
+		t.Errorf("got Next() %v after an error, want %v", g, e)
+	}
+}
+
+// valuePage implements Page with value receivers.
+type valuePage struct{}
+
+func (valuePage) NextPageURL() string { return "" }
+func (valuePage) Len() int            { return 0 }
+
+func TestPager_NotPointer(t *testing.T) {
+	cli := &Client{}
+
+	pager := cli.NewPager(func(ctx context.Context) (Page, error) {
+		return valuePage{}, nil
+	})
+
+	if g, e := pager.Next(context.TODO()), false; g != e {
+		t.Fatalf("got Next() %v, want %v", g, e)
+	}
+
+	if pager.Err() == nil {
+		t.Errorf("Err() should return an error if PageFunc returns a non-pointer page")
+	}
+}
@@ -0,0 +1,402 @@
+// Package pixivtest provides utilities for testing code built on go-pixiv
+// without access to the live API.
+package pixivtest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Redacted replaces every secret written to a cassette.
+const Redacted = "REDACTED"
+
+// RedactedHeaders are the request and response headers whose values are
+// replaced with Redacted when recording.
+var RedactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// RedactedParams are the query, form and JSON body keys whose values are
+// replaced with Redacted when recording.
+var RedactedParams = []string{"access_token", "refresh_token", "password", "client_secret"}
+
+// Cassette is a sequence of recorded HTTP interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+	Body   Body        `json:"body"`
+}
+
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       Body        `json:"body"`
+}
+
+// Body is stored as a JSON string when it is valid UTF-8, which keeps API
+// responses readable, and as base64 otherwise, e.g. for images.
+type Body []byte
+
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Body(s)
+		return nil
+	}
+
+	var enc struct {
+		Base64 string `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return err
+	}
+
+	buf, err := base64.StdEncoding.DecodeString(enc.Base64)
+	if err != nil {
+		return err
+	}
+
+	*b = Body(buf)
+	return nil
+}
+
+func LoadCassette(path string) (*Cassette, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(buf, &c); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette %s: %v", path, err)
+	}
+
+	return &c, nil
+}
+
+// Save writes the cassette to path, creating parent directories as needed.
+func (c *Cassette) Save(path string) error {
+	buf, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(buf, '\n'), 0644)
+}
+
+// Recorder is an http.RoundTripper that forwards requests to Transport and
+// records every interaction with secrets redacted. Use it as the transport
+// of the *http.Client given to both pixiv.Client and
+// pixiv.OauthTokenProvider to capture a whole session.
+type Recorder struct {
+	// Transport performs the actual requests. If nil,
+	// http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	mx       sync.Mutex
+	cassette Cassette
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		buf, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = buf
+
+		// A RoundTripper must not modify the request, so the body is
+		// replayed to the underlying transport on a shallow copy.
+		orig := req
+		req = new(http.Request)
+		*req = *orig
+		req.Body = ioutil.NopCloser(bytes.NewReader(buf))
+	}
+
+	res, err := r.transport().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+
+	interaction := Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    redactURL(req.URL),
+			Header: redactHeader(req.Header),
+			Body:   redactBody(req.Header.Get("Content-Type"), reqBody),
+		},
+		Response: Response{
+			StatusCode: res.StatusCode,
+			Header:     redactHeader(res.Header),
+			Body:       redactBody(res.Header.Get("Content-Type"), resBody),
+		},
+	}
+
+	r.mx.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mx.Unlock()
+
+	return res, nil
+}
+
+// Cassette returns a copy of the interactions recorded so far.
+func (r *Recorder) Cassette() *Cassette {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	interactions := make([]Interaction, len(r.cassette.Interactions))
+	copy(interactions, r.cassette.Interactions)
+
+	return &Cassette{Interactions: interactions}
+}
+
+// Save writes the interactions recorded so far to path.
+func (r *Recorder) Save(path string) error {
+	return r.Cassette().Save(path)
+}
+
+func (r *Recorder) transport() http.RoundTripper {
+	if r.Transport == nil {
+		return http.DefaultTransport
+	}
+	return r.Transport
+}
+
+// Replayer is an http.RoundTripper that serves responses from a cassette
+// without touching the network. Requests are matched by method, path and
+// query; headers and bodies are ignored, so redacted credentials do not
+// matter. Interactions sharing a key are served in the order they were
+// recorded, and a request with no interaction left fails.
+type Replayer struct {
+	mx    sync.Mutex
+	queue map[string][]Interaction
+}
+
+func NewReplayer(c *Cassette) (*Replayer, error) {
+	queue := map[string][]Interaction{}
+
+	for _, i := range c.Interactions {
+		u, err := url.Parse(i.Request.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recorded URL %q: %v", i.Request.URL, err)
+		}
+
+		k := replayKey(i.Request.Method, u)
+		queue[k] = append(queue[k], i)
+	}
+
+	return &Replayer{queue: queue}, nil
+}
+
+// LoadReplayer is a shorthand for LoadCassette followed by NewReplayer.
+func LoadReplayer(path string) (*Replayer, error) {
+	c, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReplayer(c)
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	k := replayKey(req.Method, req.URL)
+
+	r.mx.Lock()
+	interactions := r.queue[k]
+	if len(interactions) == 0 {
+		r.mx.Unlock()
+		return nil, fmt.Errorf("no recorded interaction left for %s", k)
+	}
+	i := interactions[0]
+	r.queue[k] = interactions[1:]
+	r.mx.Unlock()
+
+	header := http.Header{}
+	for k, v := range i.Response.Header {
+		header[k] = append([]string(nil), v...)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Response.StatusCode, http.StatusText(i.Response.StatusCode)),
+		StatusCode:    i.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(i.Response.Body)),
+		ContentLength: int64(len(i.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Remaining returns the number of interactions that have not been replayed
+// yet.
+func (r *Replayer) Remaining() int {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	n := 0
+	for _, interactions := range r.queue {
+		n += len(interactions)
+	}
+	return n
+}
+
+// replayKey identifies a request by method, path and query. The query is
+// re-encoded so that parameter order does not matter, and redacted so that
+// requests carrying secrets in the query still match their recording.
+func replayKey(method string, u *url.URL) string {
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	k := method + " " + u.Path
+	if q := redactValues(u.Query()).Encode(); q != "" {
+		k += "?" + q
+	}
+	return k
+}
+
+func redactURL(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+	redacted.RawQuery = redactValues(u.Query()).Encode()
+	return redacted.String()
+}
+
+func redactHeader(h http.Header) http.Header {
+	redacted := http.Header{}
+	for k, v := range h {
+		redacted[k] = append([]string(nil), v...)
+	}
+
+	for _, k := range RedactedHeaders {
+		if _, ok := redacted[http.CanonicalHeaderKey(k)]; ok {
+			redacted.Set(k, Redacted)
+		}
+	}
+
+	return redacted
+}
+
+func redactValues(v url.Values) url.Values {
+	for _, k := range RedactedParams {
+		if _, ok := v[k]; ok {
+			v.Set(k, Redacted)
+		}
+	}
+	return v
+}
+
+func redactBody(contentType string, body []byte) Body {
+	switch {
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		v, err := url.ParseQuery(string(body))
+		if err != nil {
+			return Body(body)
+		}
+		return Body(redactValues(v).Encode())
+	case strings.HasPrefix(contentType, "application/json"):
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			return Body(body)
+		}
+
+		if !redactJSON(v) {
+			return Body(body)
+		}
+
+		buf, err := json.Marshal(v)
+		if err != nil {
+			return Body(body)
+		}
+		return Body(buf)
+	}
+
+	return Body(body)
+}
+
+// redactJSON replaces the values of RedactedParams keys at any depth of v
+// and reports whether anything was replaced.
+func redactJSON(v interface{}) bool {
+	redacted := false
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, elem := range v {
+			if isRedactedParam(k) {
+				if _, ok := elem.(string); ok {
+					v[k] = Redacted
+					redacted = true
+					continue
+				}
+			}
+			if redactJSON(elem) {
+				redacted = true
+			}
+		}
+	case []interface{}:
+		for _, elem := range v {
+			if redactJSON(elem) {
+				redacted = true
+			}
+		}
+	}
+
+	return redacted
+}
+
+func isRedactedParam(k string) bool {
+	for _, p := range RedactedParams {
+		if k == p {
+			return true
+		}
+	}
+	return false
+}
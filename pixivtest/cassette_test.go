@@ -0,0 +1,219 @@
+package pixivtest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/search2d/go-pixiv"
+)
+
+func fixture(path string) []byte {
+	buf, err := ioutil.ReadFile(filepath.Join("..", path))
+	if err != nil {
+		panic(err)
+	}
+
+	return buf
+}
+
+func TestRecorder_Replayer(t *testing.T) {
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/auth/token":
+			w.Write(fixture("fixtures/token_authorize.json"))
+		case "/v1/illust/detail":
+			cnt++
+			if cnt == 1 {
+				w.Write(fixture("fixtures/get_illust_detail_1.json"))
+			} else {
+				w.Write(fixture("fixtures/get_illust_detail_2.json"))
+			}
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	session := func(t *testing.T, transport http.RoundTripper) []*pixiv.GetIllustDetail {
+		hc := &http.Client{Transport: transport}
+
+		cli := &pixiv.Client{
+			Client:  hc,
+			BaseURL: ts.URL,
+			TokenProvider: &pixiv.OauthTokenProvider{
+				Client:  hc,
+				BaseURL: ts.URL,
+				Credential: pixiv.Credential{
+					Username:     "USERNAME",
+					Password:     "PASSWORD",
+					ClientID:     "CLIENT_ID",
+					ClientSecret: "CLIENT_SECRET",
+				},
+			},
+		}
+
+		details := []*pixiv.GetIllustDetail{}
+		for i := 0; i < 2; i++ {
+			detail, err := cli.GetIllustDetail(context.TODO(), pixiv.NewGetIllustDetailParams().SetIllustID(1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			details = append(details, detail)
+		}
+		return details
+	}
+
+	rec := &Recorder{}
+	recorded := session(t, rec)
+
+	dir, err := ioutil.TempDir("", "pixivtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cassettes", "session.json")
+	if err := rec.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, secret := range []string{
+		"PASSWORD",
+		"CLIENT_SECRET",
+		"ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY",
+		"wgNv1gZ0y8Z1nIyG4bRbpT2yNMs3hvHhHLIhXDc47G8",
+	} {
+		if strings.Contains(string(buf), secret) {
+			t.Errorf("cassette should not contain %q", secret)
+		}
+	}
+
+	ts.Close()
+
+	rep, err := LoadReplayer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replayed := session(t, rep)
+
+	if g, e := replayed, recorded; !reflect.DeepEqual(g, e) {
+		t.Errorf("got %#v, want %#v", g, e)
+	}
+
+	if recorded[0].Illust.ID == recorded[1].Illust.ID {
+		t.Errorf("repeated requests should be replayed in recorded order")
+	}
+
+	if g, e := rep.Remaining(), 0; g != e {
+		t.Errorf("got %d remaining interactions, want %d", g, e)
+	}
+}
+
+func TestReplayer_Exhausted(t *testing.T) {
+	rep, err := NewReplayer(&Cassette{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = (&http.Client{Transport: rep}).Get("https://app-api.pixiv.net/v1/illust/detail?illust_id=1")
+	if err == nil {
+		t.Fatal("RoundTrip() should return an error if no interaction is left")
+	}
+}
+
+func TestRecorder_Redact(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"response":{"access_token":"SECRET1","user":{"id":"1"}},"refresh_token":"SECRET2"}`))
+	}))
+	defer ts.Close()
+
+	rec := &Recorder{}
+
+	req, err := http.NewRequest(
+		http.MethodPost,
+		ts.URL+"/auth/token?client_secret=SECRET3&foo=bar",
+		strings.NewReader("password=SECRET4&username=USERNAME"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer SECRET5")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := (&http.Client{Transport: rec}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(body), "SECRET1") {
+		t.Errorf("the caller should receive the response body unredacted")
+	}
+
+	i := rec.Cassette().Interactions[0]
+
+	if g, e := i.Request.URL, ts.URL+"/auth/token?client_secret=REDACTED&foo=bar"; g != e {
+		t.Errorf("got URL %q, want %q", g, e)
+	}
+
+	if g, e := i.Request.Header.Get("Authorization"), Redacted; g != e {
+		t.Errorf("got Authorization header = %q, want %q", g, e)
+	}
+
+	if g, e := string(i.Request.Body), "password=REDACTED&username=USERNAME"; g != e {
+		t.Errorf("got request body %q, want %q", g, e)
+	}
+
+	if g, e := string(i.Response.Body), `{"refresh_token":"REDACTED","response":{"access_token":"REDACTED","user":{"id":"1"}}}`; g != e {
+		t.Errorf("got response body %q, want %q", g, e)
+	}
+}
+
+func TestBody_JSON(t *testing.T) {
+	cases := []Body{
+		Body(`{"illust":{"id":1}}`),
+		Body([]byte{0x89, 'P', 'N', 'G', 0xff, 0x00}),
+	}
+
+	for _, c := range cases {
+		var b Body
+		if err := b.UnmarshalJSON(mustMarshal(t, c)); err != nil {
+			t.Fatal(err)
+		}
+
+		if g, e := b, c; !reflect.DeepEqual(g, e) {
+			t.Errorf("got %q, want %q", g, e)
+		}
+	}
+}
+
+func mustMarshal(t *testing.T, b Body) []byte {
+	buf, err := b.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf
+}
@@ -0,0 +1,399 @@
+package pixivtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/search2d/go-pixiv"
+)
+
+// Server is an in-process fake of the Pixiv OAuth server, app API and image
+// host, all served from the same httptest.Server. Point both
+// OauthTokenProvider.BaseURL and Client.BaseURL at URL.
+//
+// Exported fields may be changed until the first request is made; use Hook
+// or the Inject methods to change behaviour while requests are in flight.
+type Server struct {
+	*httptest.Server
+
+	// Credential, if its fields are non-empty, is checked against the
+	// password and refresh grants.
+	Credential pixiv.Credential
+
+	// ExpiresIn is the lifetime in seconds of issued access tokens.
+	ExpiresIn int
+
+	// Illusts are served by /v1/illust/detail and, in order, by
+	// /v1/illust/ranking for every mode and date.
+	Illusts []pixiv.Illust
+
+	// PageSize is the number of illusts per ranking page.
+	PageSize int
+
+	// Latency delays every response.
+	Latency time.Duration
+
+	// Hook, if set, is called before every request is routed. Returning
+	// true means the hook has written the response itself.
+	Hook func(w http.ResponseWriter, r *http.Request) bool
+
+	Now func() time.Time
+
+	mx        sync.Mutex
+	seq       int
+	tokens    map[string]time.Time
+	refreshes map[string]bool
+	injected  []injection
+	requests  []*http.Request
+}
+
+type injection struct {
+	path   string
+	status int
+	body   interface{}
+	times  int
+}
+
+// NewServer starts a Server seeded with 45 illusts, so that rankings span
+// two pages.
+func NewServer() *Server {
+	s := &Server{
+		ExpiresIn: 3600,
+		PageSize:  30,
+		tokens:    map[string]time.Time{},
+		refreshes: map[string]bool{},
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	for id := 1; id <= 45; id++ {
+		s.Illusts = append(s.Illusts, s.NewIllust(id, 1))
+	}
+
+	return s
+}
+
+// NewIllust returns an illust with pageCount pages whose image URLs point at
+// the image host of s.
+func (s *Server) NewIllust(id int, pageCount int) pixiv.Illust {
+	illust := pixiv.Illust{
+		ID:             id,
+		Title:          fmt.Sprintf("illust %d", id),
+		Type:           pixiv.IllustTypeIllust,
+		ImageURLs:      s.imageURLs(id, 0),
+		User:           pixiv.IllustUser{ID: 1, Name: "user 1", Account: "user1", ProfileImageURLs: map[string]string{}},
+		Tags:           []pixiv.IllustTag{},
+		Tools:          []string{},
+		CreateDate:     "2017-09-01T00:00:00+09:00",
+		PageCount:      pageCount,
+		Width:          1,
+		Height:         1,
+		SanityLevel:    pixiv.SanityLevelWhite,
+		MetaSinglePage: map[string]string{},
+		MetaPages:      []pixiv.IllustMetaPage{},
+		Visible:        true,
+	}
+
+	if pageCount == 1 {
+		illust.MetaSinglePage["original_image_url"] = s.imageURLs(id, 0).Original
+		return illust
+	}
+
+	for page := 0; page < pageCount; page++ {
+		illust.MetaPages = append(illust.MetaPages, pixiv.IllustMetaPage{ImageURLs: s.imageURLs(id, page)})
+	}
+
+	return illust
+}
+
+func (s *Server) imageURLs(id int, page int) pixiv.ImageURLs {
+	master := fmt.Sprintf("%s/img-master/img/2017/09/01/00/00/00/%d_p%d_master1200.jpg", s.URL, id, page)
+
+	return pixiv.ImageURLs{
+		SquareMedium: fmt.Sprintf("%s/img-master/img/2017/09/01/00/00/00/%d_p%d_square1200.jpg", s.URL, id, page),
+		Medium:       master,
+		Large:        master,
+		Original:     fmt.Sprintf("%s/img-original/img/2017/09/01/00/00/00/%d_p%d.png", s.URL, id, page),
+	}
+}
+
+// InjectError makes the next times requests to path fail with status and an
+// APIErrorBody carrying message.
+func (s *Server) InjectError(path string, status int, message string, times int) {
+	s.inject(path, status, pixiv.APIErrorBody{Error: pixiv.APIError{Message: message, UserMessageDetails: map[string]interface{}{}}}, times)
+}
+
+// InjectRateLimit makes the next times requests to path fail the way Pixiv
+// does when the request quota is exceeded.
+func (s *Server) InjectRateLimit(path string, times int) {
+	s.InjectError(path, http.StatusForbidden, "Rate Limit", times)
+}
+
+func (s *Server) inject(path string, status int, body interface{}, times int) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.injected = append(s.injected, injection{path: path, status: status, body: body, times: times})
+}
+
+// RevokeTokens invalidates every issued access token, as the API does when a
+// token is revoked before it expires. Refresh tokens stay valid.
+func (s *Server) RevokeTokens() {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.tokens = map[string]time.Time{}
+}
+
+// Requests returns the requests received so far, including rejected ones.
+func (s *Server) Requests() []*http.Request {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return append([]*http.Request(nil), s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mx.Lock()
+	s.requests = append(s.requests, r)
+	s.mx.Unlock()
+
+	if s.Latency > 0 {
+		select {
+		case <-time.After(s.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if s.Hook != nil && s.Hook(w, r) {
+		return
+	}
+
+	if s.serveInjected(w, r) {
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/auth/token":
+		s.serveToken(w, r)
+	case r.URL.Path == "/v1/illust/ranking":
+		s.authorized(s.serveRanking)(w, r)
+	case r.URL.Path == "/v1/illust/detail":
+		s.authorized(s.serveDetail)(w, r)
+	case strings.HasPrefix(r.URL.Path, "/img-original/"), strings.HasPrefix(r.URL.Path, "/img-master/"):
+		s.serveImage(w, r)
+	default:
+		writeJSON(w, http.StatusNotFound, pixiv.APIErrorBody{Error: pixiv.APIError{
+			UserMessage:        "指定されたエンドポイントは存在しません",
+			UserMessageDetails: map[string]interface{}{},
+		}})
+	}
+}
+
+func (s *Server) serveInjected(w http.ResponseWriter, r *http.Request) bool {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	for i, inj := range s.injected {
+		if inj.path != r.URL.Path {
+			continue
+		}
+
+		if inj.times--; inj.times <= 0 {
+			s.injected = append(s.injected[:i], s.injected[i+1:]...)
+		} else {
+			s.injected[i] = inj
+		}
+
+		writeJSON(w, inj.status, inj.body)
+		return true
+	}
+
+	return false
+}
+
+func (s *Server) serveToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	c := s.Credential
+
+	if (c.ClientID != "" && r.PostForm.Get("client_id") != c.ClientID) ||
+		(c.ClientSecret != "" && r.PostForm.Get("client_secret") != c.ClientSecret) {
+		writeTokenError(w, "Invalid client")
+		return
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	switch r.PostForm.Get("grant_type") {
+	case "password":
+		if (c.Username != "" && r.PostForm.Get("username") != c.Username) ||
+			(c.Password != "" && r.PostForm.Get("password") != c.Password) {
+			writeTokenError(w, "103:pixiv ID、またはメールアドレス、パスワードが正しいかチェックしてください。")
+			return
+		}
+	case "refresh_token":
+		rt := r.PostForm.Get("refresh_token")
+		if !s.refreshes[rt] && (c.RefreshToken == "" || rt != c.RefreshToken) {
+			writeTokenError(w, "Invalid refresh token")
+			return
+		}
+	default:
+		writeTokenError(w, "Invalid grant_type parameter or parameter missing")
+		return
+	}
+
+	s.seq++
+	accessToken := fmt.Sprintf("ACCESS_TOKEN_%d", s.seq)
+	refreshToken := fmt.Sprintf("REFRESH_TOKEN_%d", s.seq)
+
+	s.tokens[accessToken] = s.now().Add(time.Duration(s.ExpiresIn) * time.Second)
+	s.refreshes[refreshToken] = true
+
+	writeJSON(w, http.StatusOK, pixiv.Token{Response: pixiv.TokenResponse{
+		AccessToken:  accessToken,
+		ExpiresIn:    s.ExpiresIn,
+		TokenType:    "bearer",
+		RefreshToken: refreshToken,
+		User:         pixiv.TokenUser{ProfileImageURLs: map[string]string{}, ID: "1", Name: "user 1", Account: "user1"},
+	}})
+}
+
+// authorized rejects requests without a live access token the way the API
+// does, which is what Client recognizes as an auth failure.
+func (s *Server) authorized(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		s.mx.Lock()
+		expiresAt, ok := s.tokens[token]
+		s.mx.Unlock()
+
+		if !ok || !s.now().Before(expiresAt) {
+			writeJSON(w, http.StatusBadRequest, pixiv.APIErrorBody{Error: pixiv.APIError{
+				Message:            "Error occurred at the OAuth process. Please check your Access Token to fix this. Error Message: invalid_grant",
+				UserMessageDetails: map[string]interface{}{},
+			}})
+			return
+		}
+
+		h(w, r)
+	}
+}
+
+func (s *Server) serveRanking(w http.ResponseWriter, r *http.Request) {
+	if r.Form.Get("mode") == "" {
+		writeJSON(w, http.StatusBadRequest, pixiv.APIErrorBody{Error: pixiv.APIError{
+			Message:            `{"mode":["Mode is invalid"]}`,
+			UserMessageDetails: map[string]interface{}{},
+		}})
+		return
+	}
+
+	offset, _ := strconv.Atoi(r.Form.Get("offset"))
+	if offset < 0 || offset > len(s.Illusts) {
+		offset = len(s.Illusts)
+	}
+
+	end := offset + s.PageSize
+	if end > len(s.Illusts) {
+		end = len(s.Illusts)
+	}
+
+	res := pixiv.GetIllustRanking{Illusts: append([]pixiv.Illust{}, s.Illusts[offset:end]...)}
+
+	if end < len(s.Illusts) {
+		q := url.Values{}
+		for k, v := range r.URL.Query() {
+			q[k] = v
+		}
+		q.Set("offset", strconv.Itoa(end))
+
+		res.NextURL = s.URL + r.URL.Path + "?" + q.Encode()
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) serveDetail(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(r.Form.Get("illust_id"))
+
+	for _, illust := range s.Illusts {
+		if illust.ID == id {
+			writeJSON(w, http.StatusOK, pixiv.GetIllustDetail{Illust: illust})
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusNotFound, pixiv.APIErrorBody{Error: pixiv.APIError{
+		UserMessage:        "該当作品は削除されたか、存在しない作品IDです。",
+		UserMessageDetails: map[string]interface{}{},
+	}})
+}
+
+// serveImage serves a generated PNG for any image path. Like i.pximg.net, it
+// refuses requests without a Referer. Range requests are supported.
+func (s *Server) serveImage(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Referer") == "" {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	sum := crc32.ChecksumIEEE([]byte(r.URL.Path))
+
+	img := image.NewGray(image.Rect(0, 0, 64, 64))
+	for i := range img.Pix {
+		img.Pix[i] = byte(sum >> uint(i%4*8))
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("ETag", fmt.Sprintf(`"%08x"`, sum))
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(buf.Bytes()))
+}
+
+func (s *Server) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+	return s.Now()
+}
+
+func writeTokenError(w http.ResponseWriter, message string) {
+	writeJSON(w, http.StatusBadRequest, pixiv.TokenErrorBody{
+		HasError: true,
+		Errors:   map[string]pixiv.TokenError{"system": {Message: message, Code: 1508}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,272 @@
+package pixivtest
+
+import (
+	"context"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/search2d/go-pixiv"
+)
+
+func newTestClient(s *Server) (*pixiv.Client, *pixiv.OauthTokenProvider) {
+	tp := &pixiv.OauthTokenProvider{
+		BaseURL: s.URL,
+		Credential: pixiv.Credential{
+			Username:     "USERNAME",
+			Password:     "PASSWORD",
+			ClientID:     "CLIENT_ID",
+			ClientSecret: "CLIENT_SECRET",
+		},
+	}
+
+	return &pixiv.Client{BaseURL: s.URL, TokenProvider: tp}, tp
+}
+
+func grantTypes(s *Server) []string {
+	grants := []string{}
+	for _, r := range s.Requests() {
+		if r.URL.Path == "/auth/token" {
+			grants = append(grants, r.PostForm.Get("grant_type"))
+		}
+	}
+	return grants
+}
+
+func TestServer_Ranking(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	cli, _ := newTestClient(s)
+
+	pager := cli.NewPager(func(ctx context.Context) (pixiv.Page, error) {
+		return cli.GetIllustRanking(ctx, pixiv.NewGetIllustRankingParams().SetMode(pixiv.RankingModeDay).SetDate("2017-09-01"))
+	})
+
+	ids := []int{}
+	pages := 0
+	for pager.Next(context.TODO()) {
+		pages++
+		for _, illust := range pager.Page().(*pixiv.GetIllustRanking).Illusts {
+			ids = append(ids, illust.ID)
+		}
+	}
+
+	if err := pager.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := pages, 2; g != e {
+		t.Errorf("got %d pages, want %d", g, e)
+	}
+
+	for i, id := range ids {
+		if id != i+1 {
+			t.Fatalf("got illust IDs %v, want 1 to 45 in order", ids)
+		}
+	}
+
+	if g, e := len(ids), 45; g != e {
+		t.Errorf("got %d illusts, want %d", g, e)
+	}
+}
+
+func TestServer_Detail(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.Illusts = append(s.Illusts, s.NewIllust(100, 3))
+
+	cli, _ := newTestClient(s)
+
+	detail, err := cli.GetIllustDetail(context.TODO(), pixiv.NewGetIllustDetailParams().SetIllustID(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := detail.Illust, s.Illusts[45]; !reflect.DeepEqual(g, e) {
+		t.Errorf("got %#v, want %#v", g, e)
+	}
+
+	_, err = cli.GetIllustDetail(context.TODO(), pixiv.NewGetIllustDetailParams().SetIllustID(101))
+
+	errAPI, ok := err.(pixiv.ErrAPI)
+	if !ok {
+		t.Fatalf("GetIllustDetail() should return an ErrAPI for an unknown illust, got %v", err)
+	}
+
+	if g, e := errAPI.StatusCode, http.StatusNotFound; g != e {
+		t.Errorf("got StatusCode %d, want %d", g, e)
+	}
+}
+
+func TestServer_TokenExpiry(t *testing.T) {
+	now := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	s := NewServer()
+	defer s.Close()
+
+	s.ExpiresIn = 60
+	s.Now = clock
+
+	cli, tp := newTestClient(s)
+	tp.Now = clock
+
+	params := pixiv.NewGetIllustDetailParams().SetIllustID(1)
+
+	if _, err := cli.GetIllustDetail(context.TODO(), params); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	if _, err := cli.GetIllustDetail(context.TODO(), params); err != nil {
+		t.Fatal(err)
+	}
+
+	// A token revoked before it expires is rejected by the API and replaced
+	// by Client.
+	s.RevokeTokens()
+
+	if _, err := cli.GetIllustDetail(context.TODO(), params); err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := grantTypes(s), []string{"password", "refresh_token", "refresh_token"}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got grant types %v, want %v", g, e)
+	}
+}
+
+func TestServer_Credential(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.Credential = pixiv.Credential{Username: "USERNAME", Password: "WRONG"}
+
+	_, tp := newTestClient(s)
+
+	_, err := tp.Token(context.TODO())
+	if _, ok := err.(pixiv.ErrToken); !ok {
+		t.Fatalf("Token() should return an ErrToken for a wrong password, got %v", err)
+	}
+}
+
+func TestServer_InjectRateLimit(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.InjectRateLimit("/v1/illust/detail", 1)
+
+	cli, _ := newTestClient(s)
+
+	params := pixiv.NewGetIllustDetailParams().SetIllustID(1)
+
+	_, err := cli.GetIllustDetail(context.TODO(), params)
+
+	errAPI, ok := err.(pixiv.ErrAPI)
+	if !ok {
+		t.Fatalf("GetIllustDetail() should return an ErrAPI, got %v", err)
+	}
+
+	if g, e := errAPI.StatusCode, http.StatusForbidden; g != e {
+		t.Errorf("got StatusCode %d, want %d", g, e)
+	}
+
+	if g, e := errAPI.Body.Error.Message, "Rate Limit"; g != e {
+		t.Errorf("got message %q, want %q", g, e)
+	}
+
+	if _, err := cli.GetIllustDetail(context.TODO(), params); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServer_Hook(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.Hook = func(w http.ResponseWriter, r *http.Request) bool {
+		if r.URL.Path != "/v1/illust/ranking" {
+			return false
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return true
+	}
+
+	cli, _ := newTestClient(s)
+
+	_, err := cli.GetIllustRanking(context.TODO(), pixiv.NewGetIllustRankingParams().SetMode(pixiv.RankingModeDay))
+
+	errAPI, ok := err.(pixiv.ErrAPI)
+	if !ok {
+		t.Fatalf("GetIllustRanking() should return an ErrAPI, got %v", err)
+	}
+
+	if g, e := errAPI.StatusCode, http.StatusServiceUnavailable; g != e {
+		t.Errorf("got StatusCode %d, want %d", g, e)
+	}
+}
+
+func TestServer_Latency(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.Latency = time.Second
+
+	_, tp := newTestClient(s)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := tp.Token(ctx); err == nil {
+		t.Fatal("Token() should return an error if the context expires first")
+	}
+}
+
+func TestServer_Image(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	dir, err := ioutil.TempDir("", "pixivtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	d := &pixiv.Downloader{Dir: dir}
+
+	names, err := d.DownloadIllust(context.TODO(), s.NewIllust(1, 2), pixiv.ImageSizeOriginal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := names, []string{"1_p0.png", "1_p1.png"}; !reflect.DeepEqual(g, e) {
+		t.Fatalf("got %v, want %v", g, e)
+	}
+
+	f, err := os.Open(filepath.Join(dir, names[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, err := png.Decode(f); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := http.Get(s.NewIllust(1, 1).ImageURLs.Original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if g, e := res.StatusCode, http.StatusForbidden; g != e {
+		t.Errorf("got status code %d without Referer, want %d", g, e)
+	}
+}
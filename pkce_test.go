@@ -0,0 +1,167 @@
+package pixiv
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestNewPKCE(t *testing.T) {
+	pkce, err := NewPKCE()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := len(pkce.CodeVerifier), 43; g != e {
+		t.Errorf("got code verifier length %d, want %d", g, e)
+	}
+
+	if g, e := pkce.CodeChallenge, codeChallenge(pkce.CodeVerifier); g != e {
+		t.Errorf("got code challenge %q, want %q", g, e)
+	}
+
+	another, err := NewPKCE()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pkce.CodeVerifier == another.CodeVerifier {
+		t.Errorf("NewPKCE() should generate a different code verifier each time")
+	}
+}
+
+func TestCodeChallenge(t *testing.T) {
+	// RFC 7636 Appendix B
+	if g, e := codeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"), "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"; g != e {
+		t.Errorf("got code challenge %q, want %q", g, e)
+	}
+}
+
+func TestPKCE_LoginURL(t *testing.T) {
+	pkce := &PKCE{CodeVerifier: "VERIFIER", CodeChallenge: "CHALLENGE"}
+
+	u, err := url.Parse(pkce.LoginURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := u.Scheme+"://"+u.Host+u.Path, DefaultLoginURL; g != e {
+		t.Errorf("got login URL %q, want %q", g, e)
+	}
+
+	expectedQuery := url.Values{
+		"code_challenge":        []string{"CHALLENGE"},
+		"code_challenge_method": []string{"S256"},
+		"client":                []string{"pixiv-android"},
+	}
+	if g, e := u.Query(), expectedQuery; !reflect.DeepEqual(g, e) {
+		t.Errorf("got query %#v, want %#v", g, e)
+	}
+}
+
+func TestOauthTokenProvider_LoginWithCode(t *testing.T) {
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			cnt++
+		}()
+
+		if cnt > 0 {
+			t.Fatal("too many requests")
+		}
+
+		if g, e := r.URL.Path, "/auth/token"; g != e {
+			t.Errorf("got URL path %q, want %q", g, e)
+		}
+
+		if g, e := r.Method, http.MethodPost; g != e {
+			t.Errorf("got HTTP method %q, want %q", g, e)
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		expectedForm := url.Values{
+			"code":           []string{"CODE"},
+			"code_verifier":  []string{"VERIFIER"},
+			"client_id":      []string{"CLIENT_ID"},
+			"client_secret":  []string{"CLIENT_SECRET"},
+			"grant_type":     []string{"authorization_code"},
+			"include_policy": []string{"true"},
+			"redirect_uri":   []string{DefaultRedirectURI},
+		}
+		if g, e := r.Form, expectedForm; !reflect.DeepEqual(g, e) {
+			t.Errorf("got form %#v, want %#v", g, e)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture("fixtures/token_authorize.json"))
+	}))
+	defer ts.Close()
+
+	tp := &OauthTokenProvider{
+		BaseURL: ts.URL,
+		Credential: Credential{
+			ClientID:     "CLIENT_ID",
+			ClientSecret: "CLIENT_SECRET",
+		},
+	}
+
+	if err := tp.LoginWithCode(context.TODO(), "CODE", "VERIFIER"); err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := tp.Token(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := token, "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"; g != e {
+		t.Errorf("got token %q, want %q", g, e)
+	}
+
+	if g, e := tp.RefreshToken(), "wgNv1gZ0y8Z1nIyG4bRbpT2yNMs3hvHhHLIhXDc47G8"; g != e {
+		t.Errorf("got refresh token %q, want %q", g, e)
+	}
+}
+
+func TestOauthTokenProvider_LoginWithCode_SaveError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture("fixtures/token_authorize.json"))
+	}))
+	defer ts.Close()
+
+	store := &failingTokenStore{err: errors.New("disk full")}
+
+	var saveErrs []error
+
+	tp := &OauthTokenProvider{
+		BaseURL: ts.URL,
+		Credential: Credential{
+			ClientID:     "CLIENT_ID",
+			ClientSecret: "CLIENT_SECRET",
+		},
+		Store: store,
+		OnSaveError: func(err error) {
+			saveErrs = append(saveErrs, err)
+		},
+	}
+
+	if err := tp.LoginWithCode(context.TODO(), "CODE", "VERIFIER"); err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := tp.RefreshToken(), "wgNv1gZ0y8Z1nIyG4bRbpT2yNMs3hvHhHLIhXDc47G8"; g != e {
+		t.Errorf("got refresh token %q, want %q", g, e)
+	}
+
+	if g, e := saveErrs, []error{store.err}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got save errors %v, want %v", g, e)
+	}
+}
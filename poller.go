@@ -0,0 +1,166 @@
+package pixiv
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Poller polls a feed of illusts, newest first, and returns only the works
+// it has not seen yet. It remembers the highest illust ID handled so far in
+// LastSeenID, which can be saved and restored to resume polling.
+//
+//	poller := &pixiv.Poller{
+//		Client: cli,
+//		Feed: func(ctx context.Context) (pixiv.Page, error) {
+//			return cli.GetIllustFollow(ctx, pixiv.NewGetIllustFollowParams())
+//		},
+//		Interval: 5 * time.Minute,
+//	}
+//	err := poller.Run(ctx, func(res *pixiv.PollResult) error {
+//		...
+//	})
+//
+// A Poller must not be used concurrently.
+type Poller struct {
+	Client *Client
+
+	// Feed fetches the first page of the feed. The pages must implement
+	// IllustPage.
+	Feed PageFunc
+
+	// Interval is the time between polls in Run. Zero means one minute.
+	Interval time.Duration
+
+	// MaxPages limits the number of pages fetched by a poll to catch up
+	// with the feed. Zero means 5. When LastSeenID is zero only the first
+	// page is fetched.
+	MaxPages int
+
+	// LastSeenID is the highest illust ID handled so far.
+	LastSeenID int
+}
+
+// PollResult is the result of a poll.
+type PollResult struct {
+	// Illusts are the illusts with an ID higher than LastSeenID, oldest
+	// first.
+	Illusts []Illust
+
+	// LastSeenID is the value of Poller.LastSeenID once Illusts have been
+	// handled.
+	LastSeenID int
+
+	// Truncated reports that MaxPages was reached before a seen illust
+	// showed up. The unseen illusts older than Illusts were not fetched and
+	// are skipped once LastSeenID is advanced.
+	Truncated bool
+}
+
+// Poll fetches the feed and returns the illusts with an ID higher than
+// LastSeenID. Pages are followed until a page contains a seen illust or
+// MaxPages is reached.
+//
+// Poll does not change LastSeenID. The caller sets it to the LastSeenID of
+// the result once the illusts have been handled.
+func (p *Poller) Poll(ctx context.Context) (*PollResult, error) {
+	last := p.LastSeenID
+
+	maxPages := p.maxPages()
+	if last == 0 {
+		maxPages = 1
+	}
+
+	pager := p.Client.NewPager(p.Feed).
+		SetMaxPages(maxPages).
+		SetWhile(func(page Page) bool {
+			ip, ok := page.(IllustPage)
+			if !ok {
+				return false
+			}
+			illusts := ip.PageIllusts()
+			return len(illusts) > 0 && illusts[len(illusts)-1].ID > last
+		})
+
+	res := &PollResult{Illusts: []Illust{}, LastSeenID: last}
+	seen := map[int]bool{}
+
+	var page IllustPage
+	for pager.Next(ctx) {
+		var ok bool
+		page, ok = pager.Page().(IllustPage)
+		if !ok {
+			return nil, fmt.Errorf("poller feed returned %T, which is not an IllustPage", pager.Page())
+		}
+
+		for _, illust := range page.PageIllusts() {
+			if illust.ID > last && !seen[illust.ID] {
+				seen[illust.ID] = true
+				res.Illusts = append(res.Illusts, illust)
+			}
+		}
+	}
+
+	if err := pager.Err(); err != nil {
+		return nil, err
+	}
+
+	// The first poll reads only the first page on purpose, so nothing is
+	// reported as skipped.
+	if last != 0 && page != nil && page.NextPageURL() != "" {
+		illusts := page.PageIllusts()
+		res.Truncated = len(illusts) > 0 && illusts[len(illusts)-1].ID > last
+	}
+
+	sort.Slice(res.Illusts, func(i, j int) bool { return res.Illusts[i].ID < res.Illusts[j].ID })
+
+	if len(res.Illusts) > 0 {
+		res.LastSeenID = res.Illusts[len(res.Illusts)-1].ID
+	}
+
+	return res, nil
+}
+
+// Run polls immediately and then every Interval, calling fn with the result
+// of every poll that found unseen illusts. LastSeenID is advanced only after
+// fn succeeds, so the illusts of a failed call are returned again when Run
+// is called to resume. It returns when ctx is done, or a poll or fn fails.
+func (p *Poller) Run(ctx context.Context, fn func(res *PollResult) error) error {
+	ticker := time.NewTicker(p.interval())
+	defer ticker.Stop()
+
+	for {
+		res, err := p.Poll(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(res.Illusts) > 0 {
+			if err := fn(res); err != nil {
+				return err
+			}
+		}
+		p.LastSeenID = res.LastSeenID
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (p *Poller) interval() time.Duration {
+	if p.Interval <= 0 {
+		return time.Minute
+	}
+	return p.Interval
+}
+
+func (p *Poller) maxPages() int {
+	if p.MaxPages == 0 {
+		return 5
+	}
+	return p.MaxPages
+}
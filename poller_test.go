@@ -0,0 +1,278 @@
+package pixiv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// feedServer serves /v1/illust/new from ids, newest first, paginated by
+// max_illust_id.
+type feedServer struct {
+	*httptest.Server
+
+	mx       sync.Mutex
+	ids      []int
+	pageSize int
+	requests int
+}
+
+func newFeedServer(ids []int) *feedServer {
+	s := &feedServer{ids: ids, pageSize: 3}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+func (s *feedServer) post(ids ...int) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.ids = append(ids, s.ids...)
+}
+
+func (s *feedServer) numRequests() int {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return s.requests
+}
+
+func (s *feedServer) serve(w http.ResponseWriter, r *http.Request) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.requests++
+
+	max := -1
+	if v := r.URL.Query().Get("max_illust_id"); v != "" {
+		max, _ = strconv.Atoi(v)
+	}
+
+	var result GetIllustNew
+	for _, id := range s.ids {
+		if max >= 0 && id > max {
+			continue
+		}
+		if len(result.Illusts) == s.pageSize {
+			result.NextURL = fmt.Sprintf("%s/v1/illust/new?max_illust_id=%d", s.URL, id)
+			break
+		}
+		result.Illusts = append(result.Illusts, Illust{ID: id})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func newTestPoller(ts *feedServer) *Poller {
+	cli := &Client{
+		TokenProvider: &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"},
+		BaseURL:       ts.URL,
+	}
+
+	return &Poller{
+		Client: cli,
+		Feed: func(ctx context.Context) (Page, error) {
+			return cli.GetIllustNew(ctx, NewGetIllustNewParams())
+		},
+	}
+}
+
+func TestPoller_Poll(t *testing.T) {
+	ts := newFeedServer([]int{10, 9, 8, 7, 6, 5, 4})
+	defer ts.Close()
+
+	p := newTestPoller(ts)
+
+	// The first poll only reads the first page.
+	res, err := p.Poll(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := illustIDs(res.Illusts), []int{8, 9, 10}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got illust IDs %v, want %v", g, e)
+	}
+	if g, e := res.LastSeenID, 10; g != e {
+		t.Errorf("got LastSeenID %d, want %d", g, e)
+	}
+	if res.Truncated {
+		t.Errorf("got Truncated true, want false")
+	}
+
+	// Poll leaves LastSeenID to the caller.
+	if g, e := p.LastSeenID, 0; g != e {
+		t.Errorf("got Poller.LastSeenID %d, want %d", g, e)
+	}
+	p.LastSeenID = res.LastSeenID
+
+	res, err = p.Poll(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := illustIDs(res.Illusts), []int{}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got illust IDs %v, want %v", g, e)
+	}
+	if g, e := res.LastSeenID, 10; g != e {
+		t.Errorf("got LastSeenID %d, want %d", g, e)
+	}
+
+	// Catching up follows the pages until a seen illust shows up.
+	ts.post(15, 14, 13, 12, 11)
+
+	before := ts.numRequests()
+
+	res, err = p.Poll(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := illustIDs(res.Illusts), []int{11, 12, 13, 14, 15}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got illust IDs %v, want %v", g, e)
+	}
+	if g, e := ts.numRequests()-before, 2; g != e {
+		t.Errorf("got %d requests, want %d", g, e)
+	}
+	if g, e := res.LastSeenID, 15; g != e {
+		t.Errorf("got LastSeenID %d, want %d", g, e)
+	}
+	if res.Truncated {
+		t.Errorf("got Truncated true, want false")
+	}
+}
+
+func TestPoller_Poll_MaxPages(t *testing.T) {
+	ts := newFeedServer([]int{20, 19, 18, 17, 16, 15, 14, 13, 12, 11, 10})
+	defer ts.Close()
+
+	p := newTestPoller(ts)
+	p.MaxPages = 2
+	p.LastSeenID = 10
+
+	// Illusts 11 to 14 are beyond MaxPages, which the result reports.
+	res, err := p.Poll(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := illustIDs(res.Illusts), []int{15, 16, 17, 18, 19, 20}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got illust IDs %v, want %v", g, e)
+	}
+	if g, e := res.LastSeenID, 20; g != e {
+		t.Errorf("got LastSeenID %d, want %d", g, e)
+	}
+	if !res.Truncated {
+		t.Errorf("got Truncated false, want true")
+	}
+}
+
+func TestPoller_Poll_NotIllustPage(t *testing.T) {
+	ts := newFeedServer(nil)
+	defer ts.Close()
+
+	p := newTestPoller(ts)
+	p.Feed = func(ctx context.Context) (Page, error) {
+		return &GetUserFollowing{}, nil
+	}
+
+	if _, err := p.Poll(context.TODO()); err == nil {
+		t.Errorf("Poll() should fail if the feed does not return an IllustPage")
+	}
+}
+
+func TestPoller_Run(t *testing.T) {
+	ts := newFeedServer([]int{2, 1})
+	defer ts.Close()
+
+	p := newTestPoller(ts)
+	p.Interval = time.Millisecond
+
+	errStop := errors.New("stop")
+
+	got := [][]int{}
+	err := p.Run(context.TODO(), func(res *PollResult) error {
+		got = append(got, illustIDs(res.Illusts))
+		if len(got) == 2 {
+			return errStop
+		}
+		ts.post(4, 3)
+		return nil
+	})
+	if err != errStop {
+		t.Fatalf("got error %v, want %v", err, errStop)
+	}
+
+	if g, e := got, [][]int{{1, 2}, {3, 4}}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got polls %v, want %v", g, e)
+	}
+
+	// The illusts of the failed call are not marked as seen.
+	if g, e := p.LastSeenID, 2; g != e {
+		t.Errorf("got LastSeenID %d, want %d", g, e)
+	}
+
+	p.Interval = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	got = [][]int{}
+	err = p.Run(ctx, func(res *PollResult) error {
+		got = append(got, illustIDs(res.Illusts))
+		cancel()
+		return nil
+	})
+	if err != context.Canceled {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+
+	if g, e := got, [][]int{{3, 4}}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got polls %v, want %v", g, e)
+	}
+	if g, e := p.LastSeenID, 4; g != e {
+		t.Errorf("got LastSeenID %d, want %d", g, e)
+	}
+}
+
+func TestPoller_Run_Canceled(t *testing.T) {
+	ts := newFeedServer([]int{1})
+	defer ts.Close()
+
+	p := newTestPoller(ts)
+	p.Interval = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := p.Run(ctx, func(res *PollResult) error {
+		cancel()
+		return nil
+	})
+	if err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestPoller_Run_ZeroInterval(t *testing.T) {
+	ts := newFeedServer([]int{1})
+	defer ts.Close()
+
+	p := newTestPoller(ts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := p.Run(ctx, func(res *PollResult) error {
+		cancel()
+		return nil
+	})
+	if err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+
+	if g, e := p.interval(), time.Minute; g != e {
+		t.Errorf("got interval %v, want %v", g, e)
+	}
+}
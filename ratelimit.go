@@ -0,0 +1,184 @@
+package pixiv
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limiter throttles requests sent through Client.Do.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// AdaptiveLimiter is implemented by Limiters that slow down when the API
+// reports that the rate limit has been exceeded.
+type AdaptiveLimiter interface {
+	Limiter
+	RateLimited()
+}
+
+// TokenBucketLimiter allows Rate requests per second on average with bursts
+// of up to Burst requests. It is safe for concurrent use.
+//
+// Every time RateLimited is called the effective rate is halved, down to
+// MinRate. It doubles again after each RecoveryInterval without further
+// rate limiting until it is back at Rate.
+type TokenBucketLimiter struct {
+	Rate             float64
+	Burst            int
+	MinRate          float64
+	RecoveryInterval time.Duration
+
+	Now   func() time.Time
+	Sleep func(ctx context.Context, d time.Duration) error
+
+	mx          sync.Mutex
+	initialized bool
+	tokens      float64
+	current     float64
+	last        time.Time
+	penalizedAt time.Time
+}
+
+func NewTokenBucketLimiter(rate float64, burst int) *TokenBucketLimiter {
+	return &TokenBucketLimiter{Rate: rate, Burst: burst}
+}
+
+func (l *TokenBucketLimiter) Wait(ctx context.Context) error {
+	if l.Rate <= 0 {
+		return nil
+	}
+
+	l.mx.Lock()
+	l.advance(l.now())
+	l.tokens--
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.current * float64(time.Second))
+	}
+	l.mx.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	if err := l.sleep(ctx, wait); err != nil {
+		l.mx.Lock()
+		l.tokens++
+		l.mx.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+func (l *TokenBucketLimiter) RateLimited() {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	now := l.now()
+	l.advance(now)
+
+	l.current /= 2
+	if l.current < l.minRate() {
+		l.current = l.minRate()
+	}
+
+	if l.tokens > 0 {
+		l.tokens = 0
+	}
+
+	l.penalizedAt = now
+}
+
+// CurrentRate returns the effective rate in requests per second.
+func (l *TokenBucketLimiter) CurrentRate() float64 {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	l.advance(l.now())
+	return l.current
+}
+
+func (l *TokenBucketLimiter) advance(now time.Time) {
+	if !l.initialized {
+		l.initialized = true
+		l.tokens = float64(l.burst())
+		l.current = l.Rate
+		l.last = now
+		return
+	}
+
+	for l.current < l.Rate && now.Sub(l.penalizedAt) >= l.recoveryInterval() {
+		l.current *= 2
+		if l.current > l.Rate {
+			l.current = l.Rate
+		}
+		l.penalizedAt = l.penalizedAt.Add(l.recoveryInterval())
+	}
+
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens += elapsed.Seconds() * l.current
+		if l.tokens > float64(l.burst()) {
+			l.tokens = float64(l.burst())
+		}
+		l.last = now
+	}
+}
+
+func (l *TokenBucketLimiter) burst() int {
+	if l.Burst < 1 {
+		return 1
+	}
+	return l.Burst
+}
+
+func (l *TokenBucketLimiter) minRate() float64 {
+	if l.MinRate == 0 {
+		return l.Rate / 16
+	}
+	return l.MinRate
+}
+
+func (l *TokenBucketLimiter) recoveryInterval() time.Duration {
+	if l.RecoveryInterval == 0 {
+		return time.Minute
+	}
+	return l.RecoveryInterval
+}
+
+func (l *TokenBucketLimiter) now() time.Time {
+	if l.Now == nil {
+		return time.Now()
+	}
+	return l.Now()
+}
+
+func (l *TokenBucketLimiter) sleep(ctx context.Context, d time.Duration) error {
+	if l.Sleep == nil {
+		return sleepContext(ctx, d)
+	}
+	return l.Sleep(ctx, d)
+}
+
+// isRateLimited reports whether res indicates that the rate limit has been
+// exceeded. The response body is left unread.
+func isRateLimited(res *http.Response) bool {
+	if res.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	if res.StatusCode != http.StatusForbidden {
+		return false
+	}
+
+	body, ok := peekAPIErrorBody(res)
+	if !ok {
+		return false
+	}
+
+	return strings.Contains(body.Error.Message, "Rate Limit")
+}
@@ -0,0 +1,162 @@
+package pixiv
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	mx  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.now = c.now.Add(d)
+	return ctx.Err()
+}
+
+func TestTokenBucketLimiter_Wait(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	l := NewTokenBucketLimiter(2, 3)
+	l.Now = clock.Now
+	l.Sleep = clock.Sleep
+
+	elapsed := []time.Duration{}
+	start := clock.Now()
+
+	for i := 0; i < 6; i++ {
+		if err := l.Wait(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+		elapsed = append(elapsed, clock.Now().Sub(start))
+	}
+
+	expected := []time.Duration{
+		0,
+		0,
+		0,
+		500 * time.Millisecond,
+		1000 * time.Millisecond,
+		1500 * time.Millisecond,
+	}
+	if g, e := elapsed, expected; !reflect.DeepEqual(g, e) {
+		t.Errorf("got elapsed %v, want %v", g, e)
+	}
+}
+
+func TestTokenBucketLimiter_Wait_ContextCanceled(t *testing.T) {
+	l := NewTokenBucketLimiter(1, 1)
+
+	if err := l.Wait(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if g, e := l.Wait(ctx), context.Canceled; g != e {
+		t.Errorf("got error %v, want %v", g, e)
+	}
+}
+
+func TestTokenBucketLimiter_RateLimited(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	l := &TokenBucketLimiter{
+		Rate:             8,
+		Burst:            1,
+		MinRate:          1,
+		RecoveryInterval: time.Minute,
+		Now:              clock.Now,
+		Sleep:            clock.Sleep,
+	}
+
+	for i := 0; i < 4; i++ {
+		l.RateLimited()
+	}
+
+	if g, e := l.CurrentRate(), 1.0; g != e {
+		t.Errorf("got rate %v, want %v", g, e)
+	}
+
+	clock.Sleep(context.TODO(), 2*time.Minute)
+
+	if g, e := l.CurrentRate(), 4.0; g != e {
+		t.Errorf("got rate %v after 2 minutes, want %v", g, e)
+	}
+
+	clock.Sleep(context.TODO(), 10*time.Minute)
+
+	if g, e := l.CurrentRate(), 8.0; g != e {
+		t.Errorf("got rate %v after 12 minutes, want %v", g, e)
+	}
+}
+
+func TestClient_Do_Limiter(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			cnt++
+		}()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if cnt == 0 {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write(fixture("fixtures/api_error_rate_limit.json"))
+			return
+		}
+
+		w.Write(fixture("fixtures/get_illust_detail_1.json"))
+	}))
+	defer ts.Close()
+
+	clock := &fakeClock{now: time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	l := NewTokenBucketLimiter(4, 1)
+	l.Now = clock.Now
+	l.Sleep = clock.Sleep
+
+	cli := &Client{TokenProvider: tp, BaseURL: ts.URL, Limiter: l}
+
+	_, err := cli.GetIllustDetail(context.TODO(), NewGetIllustDetailParams().SetIllustID(1))
+
+	errAPI, ok := err.(ErrAPI)
+	if !ok {
+		t.Fatalf("GetIllustDetail() should return an ErrAPI if 403 response is received, got %#v", err)
+	}
+
+	if g, e := errAPI.Body.Error.Message, "Rate Limit"; g != e {
+		t.Errorf("got error message %q, want %q", g, e)
+	}
+
+	if g, e := l.CurrentRate(), 2.0; g != e {
+		t.Errorf("got rate %v, want %v", g, e)
+	}
+
+	start := clock.Now()
+
+	if _, err := cli.GetIllustDetail(context.TODO(), NewGetIllustDetailParams().SetIllustID(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := clock.Now().Sub(start), 500*time.Millisecond; g != e {
+		t.Errorf("got waited %v, want %v", g, e)
+	}
+}
@@ -0,0 +1,243 @@
+package pixiv
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+var DefaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy retries idempotent requests sent through Client.Do when the
+// connection fails transiently or the server responds with a retryable
+// status code.
+// Zero fields fall back to their defaults.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Jitter randomly shortens each backoff by up to this fraction (0 to 1).
+	Jitter               float64
+	RetryableStatusCodes []int
+
+	Now   func() time.Time
+	Sleep func(ctx context.Context, d time.Duration) error
+	Rand  func() float64
+}
+
+func (p *RetryPolicy) do(req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	ctx := req.Context()
+
+	r := req
+
+	for attempt := 1; ; attempt++ {
+		res, err := send(r)
+
+		if attempt >= p.maxAttempts() || !p.retryable(ctx, res, err) {
+			return res, err
+		}
+
+		// A request whose body cannot be read again is not retried.
+		next, rewindErr := rewindRequest(req)
+		if rewindErr != nil || next == nil {
+			return res, err
+		}
+
+		// The server asked for a longer wait than the policy allows, so the
+		// response is returned for the caller to see the delay.
+		wait, ok := p.backoff(attempt, res)
+		if !ok {
+			return res, err
+		}
+
+		if res != nil {
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		if err := p.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+
+		r = next
+	}
+}
+
+func (p *RetryPolicy) retryable(ctx context.Context, res *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return isTransient(err)
+	}
+
+	for _, code := range p.retryableStatusCodes() {
+		if res.StatusCode == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isTransient reports whether err is a network failure that may not happen
+// again, such as a timeout or a dropped connection. Errors from the token
+// provider, canceled contexts and other failures are not retried.
+func isTransient(err error) bool {
+	if ue, ok := err.(*url.Error); ok {
+		err = ue.Err
+	}
+
+	// The server closed a kept-alive connection.
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return true
+	}
+
+	if oe, ok := err.(*net.OpError); ok {
+		inner := oe.Err
+		if se, ok := inner.(*os.SyscallError); ok {
+			inner = se.Err
+		}
+		if inner == syscall.ECONNRESET {
+			return true
+		}
+	}
+
+	if ne, ok := err.(net.Error); ok {
+		return ne.Timeout() || ne.Temporary()
+	}
+
+	return false
+}
+
+// backoff returns the delay before the attempt following the given one.
+// A Retry-After header longer than the computed backoff takes precedence,
+// and ok is false if it is longer than MaxBackoff.
+func (p *RetryPolicy) backoff(attempt int, res *http.Response) (d time.Duration, ok bool) {
+	d = p.baseBackoff()
+	for i := 1; i < attempt && d < p.maxBackoff(); i++ {
+		d *= 2
+	}
+
+	if d > p.maxBackoff() {
+		d = p.maxBackoff()
+	}
+
+	if p.Jitter > 0 {
+		d -= time.Duration(float64(d) * p.Jitter * p.rand())
+	}
+
+	if res != nil {
+		if after, ok := retryAfter(res.Header.Get("Retry-After"), p.now()); ok && after > d {
+			if after > p.maxBackoff() {
+				return 0, false
+			}
+			d = after
+		}
+	}
+
+	return d, true
+}
+
+func retryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts == 0 {
+		return 3
+	}
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) baseBackoff() time.Duration {
+	if p.BaseBackoff == 0 {
+		return 500 * time.Millisecond
+	}
+	return p.BaseBackoff
+}
+
+func (p *RetryPolicy) maxBackoff() time.Duration {
+	if p.MaxBackoff == 0 {
+		return 30 * time.Second
+	}
+	return p.MaxBackoff
+}
+
+func (p *RetryPolicy) retryableStatusCodes() []int {
+	if p.RetryableStatusCodes == nil {
+		return DefaultRetryableStatusCodes
+	}
+	return p.RetryableStatusCodes
+}
+
+func (p *RetryPolicy) now() time.Time {
+	if p.Now == nil {
+		return time.Now()
+	}
+	return p.Now()
+}
+
+func (p *RetryPolicy) sleep(ctx context.Context, d time.Duration) error {
+	if p.Sleep == nil {
+		return sleepContext(ctx, d)
+	}
+	return p.Sleep(ctx, d)
+}
+
+func (p *RetryPolicy) rand() float64 {
+	if p.Rand == nil {
+		return rand.Float64()
+	}
+	return p.Rand()
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func isIdempotent(method string) bool {
+	return method == "" || method == http.MethodGet || method == http.MethodHead
+}
@@ -0,0 +1,370 @@
+package pixiv
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type recordingSleeper struct {
+	durations []time.Duration
+}
+
+func (s *recordingSleeper) Sleep(ctx context.Context, d time.Duration) error {
+	s.durations = append(s.durations, d)
+	return ctx.Err()
+}
+
+func TestClient_Do_RetryPolicy(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			cnt++
+		}()
+
+		switch cnt {
+		case 0:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			w.Header().Set("Retry-After", "10")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 3:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(fixture("fixtures/get_illust_detail_1.json"))
+		default:
+			t.Fatal("too many requests")
+		}
+	}))
+	defer ts.Close()
+
+	sleeper := &recordingSleeper{}
+
+	cli := &Client{
+		TokenProvider: tp,
+		BaseURL:       ts.URL,
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts: 4,
+			BaseBackoff: time.Second,
+			MaxBackoff:  20 * time.Second,
+			Jitter:      0.5,
+			Sleep:       sleeper.Sleep,
+			Rand: func() float64 {
+				return 0.5
+			},
+		},
+	}
+
+	detail, err := cli.GetIllustDetail(context.TODO(), NewGetIllustDetailParams().SetIllustID(1859785))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := detail.Illust.ID, 1859785; g != e {
+		t.Errorf("got Illust.ID %v, want %v", g, e)
+	}
+
+	expectedDurations := []time.Duration{750 * time.Millisecond, 1500 * time.Millisecond, 10 * time.Second}
+	if g, e := sleeper.durations, expectedDurations; !reflect.DeepEqual(g, e) {
+		t.Errorf("got backoffs %v, want %v", g, e)
+	}
+}
+
+func TestClient_Do_RetryPolicy_Exhausted(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cnt++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	sleeper := &recordingSleeper{}
+
+	cli := &Client{
+		TokenProvider: tp,
+		BaseURL:       ts.URL,
+		RetryPolicy:   &RetryPolicy{Sleep: sleeper.Sleep},
+	}
+
+	_, err := cli.GetIllustDetail(context.TODO(), NewGetIllustDetailParams().SetIllustID(1))
+
+	errAPI, ok := err.(ErrAPI)
+	if !ok {
+		t.Fatalf("GetIllustDetail() should return an ErrAPI once attempts are exhausted, got %#v", err)
+	}
+
+	if g, e := errAPI.StatusCode, http.StatusInternalServerError; g != e {
+		t.Errorf("got StatusCode %v, want %v", g, e)
+	}
+
+	if g, e := cnt, 3; g != e {
+		t.Errorf("got %d requests, want %d", g, e)
+	}
+
+	expectedDurations := []time.Duration{500 * time.Millisecond, time.Second}
+	if g, e := sleeper.durations, expectedDurations; !reflect.DeepEqual(g, e) {
+		t.Errorf("got backoffs %v, want %v", g, e)
+	}
+}
+
+func TestClient_Do_RetryPolicy_NotIdempotent(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cnt++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	cli := &Client{
+		TokenProvider: tp,
+		BaseURL:       ts.URL,
+		RetryPolicy:   &RetryPolicy{Sleep: (&recordingSleeper{}).Sleep},
+	}
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader("illust_id=1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := cli.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if g, e := cnt, 1; g != e {
+		t.Errorf("got %d requests, want %d", g, e)
+	}
+}
+
+func TestClient_Do_RetryPolicy_BodyNotRewindable(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cnt++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	cli := &Client{
+		TokenProvider: tp,
+		BaseURL:       ts.URL,
+		RetryPolicy:   &RetryPolicy{Sleep: (&recordingSleeper{}).Sleep},
+	}
+
+	// Wrapping the reader hides it from http.NewRequest, which leaves
+	// GetBody unset.
+	req, err := http.NewRequest(http.MethodGet, ts.URL, ioutil.NopCloser(strings.NewReader("illust_id=1")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := cli.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if g, e := res.StatusCode, http.StatusServiceUnavailable; g != e {
+		t.Errorf("got StatusCode %v, want %v", g, e)
+	}
+
+	if g, e := cnt, 1; g != e {
+		t.Errorf("got %d requests, want %d", g, e)
+	}
+}
+
+func TestClient_Do_RetryPolicy_ContextCanceled(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cli := &Client{
+		TokenProvider: tp,
+		BaseURL:       ts.URL,
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts: 10,
+			Sleep: func(ctx context.Context, d time.Duration) error {
+				cancel()
+				return sleepContext(ctx, d)
+			},
+		},
+	}
+
+	_, err := cli.GetIllustDetail(ctx, NewGetIllustDetailParams().SetIllustID(1))
+	if g, e := err, context.Canceled; g != e {
+		t.Errorf("got error %v, want %v", g, e)
+	}
+}
+
+func TestClient_Do_RetryPolicy_RetryAfterTooLong(t *testing.T) {
+	tp := &mockTokenProvider{token: "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"}
+
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cnt++
+		w.Header().Set("Retry-After", "86400")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	sleeper := &recordingSleeper{}
+
+	cli := &Client{
+		TokenProvider: tp,
+		BaseURL:       ts.URL,
+		RetryPolicy:   &RetryPolicy{MaxBackoff: 5 * time.Second, Sleep: sleeper.Sleep},
+	}
+
+	_, err := cli.GetIllustDetail(context.TODO(), NewGetIllustDetailParams().SetIllustID(1859785))
+
+	errAPI, ok := err.(ErrAPI)
+	if !ok {
+		t.Fatalf("GetIllustDetail() should return an ErrAPI, got %#v", err)
+	}
+
+	if g, e := errAPI.StatusCode, http.StatusTooManyRequests; g != e {
+		t.Errorf("got StatusCode %v, want %v", g, e)
+	}
+
+	if g, e := cnt, 1; g != e {
+		t.Errorf("got %d requests, want %d", g, e)
+	}
+
+	if g, e := len(sleeper.durations), 0; g != e {
+		t.Errorf("got %d backoffs, want %d", g, e)
+	}
+}
+
+func TestClient_Do_RetryPolicy_TokenError(t *testing.T) {
+	tp := &mockTokenProvider{err: errors.New("token store is corrupted")}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request should be sent without a token")
+	}))
+	defer ts.Close()
+
+	sleeper := &recordingSleeper{}
+
+	cli := &Client{
+		TokenProvider: tp,
+		BaseURL:       ts.URL,
+		RetryPolicy:   &RetryPolicy{Sleep: sleeper.Sleep},
+	}
+
+	if _, err := cli.GetIllustDetail(context.TODO(), NewGetIllustDetailParams().SetIllustID(1)); err != tp.err {
+		t.Errorf("got error %v, want %v", err, tp.err)
+	}
+
+	if g, e := len(sleeper.durations), 0; g != e {
+		t.Errorf("got %d retries, want %d", g, e)
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsTransient(t *testing.T) {
+	cases := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{
+			name:      "connection reset",
+			err:       &url.Error{Op: "Get", URL: "https://app-api.pixiv.net", Err: &net.OpError{Op: "read", Net: "tcp", Err: &os.SyscallError{Syscall: "read", Err: syscall.ECONNRESET}}},
+			transient: true,
+		},
+		{
+			name:      "timeout",
+			err:       &url.Error{Op: "Get", URL: "https://app-api.pixiv.net", Err: timeoutError{}},
+			transient: true,
+		},
+		{
+			name:      "connection closed",
+			err:       &url.Error{Op: "Get", URL: "https://app-api.pixiv.net", Err: io.EOF},
+			transient: true,
+		},
+		{
+			name:      "canceled",
+			err:       &url.Error{Op: "Get", URL: "https://app-api.pixiv.net", Err: context.Canceled},
+			transient: false,
+		},
+		{
+			name:      "token",
+			err:       ErrToken{StatusCode: http.StatusBadRequest},
+			transient: false,
+		},
+		{
+			name:      "other",
+			err:       errors.New("unexpected end of JSON input"),
+			transient: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if g, e := isTransient(c.err), c.transient; g != e {
+				t.Errorf("got %v, want %v", g, e)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2017, 9, 13, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		value string
+		after time.Duration
+		ok    bool
+	}{
+		{value: "", after: 0, ok: false},
+		{value: "120", after: 2 * time.Minute, ok: true},
+		{value: "-1", after: 0, ok: false},
+		{value: "Wed, 13 Sep 2017 12:00:30 GMT", after: 30 * time.Second, ok: true},
+		{value: "Wed, 13 Sep 2017 11:00:00 GMT", after: 0, ok: true},
+		{value: "soon", after: 0, ok: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.value, func(t *testing.T) {
+			after, ok := retryAfter(c.value, now)
+
+			if g, e := after, c.after; g != e {
+				t.Errorf("got %v, want %v", g, e)
+			}
+
+			if g, e := ok, c.ok; g != e {
+				t.Errorf("got ok %v, want %v", g, e)
+			}
+		})
+	}
+}
@@ -15,8 +15,17 @@ type TokenProvider interface {
 	Token(ctx context.Context) (string, error)
 }
 
+// TokenInvalidator is implemented by TokenProviders that can discard an
+// access token rejected by the API, so that the next call to Token issues
+// a fresh one.
+type TokenInvalidator interface {
+	InvalidateToken(accessToken string)
+}
+
 var DefaultOauthBaseURL = "https://oauth.secure.pixiv.net"
 
+var DefaultTokenTimeout = 30 * time.Second
+
 var DefaultOauthHeaders = map[string]string{
 	"User-Agent":     "PixivAndroidApp/5.0.64 (Android 6.0; Google Nexus 5X - 6.0.0 - API 23 - 1080x1920)",
 	"App-OS":         "android",
@@ -31,8 +40,30 @@ type OauthTokenProvider struct {
 	Credential Credential
 	Now        func() time.Time
 
-	mx    sync.Mutex
-	token *token
+	// Store, if set, is consulted for a saved token before logging in and
+	// is updated every time a new token is issued.
+	Store TokenStore
+
+	// OnSaveError, if set, is called when Store fails to save a token
+	// issued by Token or LoginWithCode. The token is used regardless, so the failure only
+	// means that it will not be reused by the next process.
+	OnSaveError func(err error)
+
+	// OnLoadError, if set, is called when Store fails to load a saved
+	// token, for example because the file is empty or corrupt. The saved
+	// token is then treated as absent and a new one is issued.
+	OnLoadError func(err error)
+
+	// Timeout bounds every token request. Zero means DefaultTokenTimeout.
+	// Token requests are detached from the contexts of the callers waiting
+	// for them, and are canceled once all of them have given up.
+	Timeout time.Duration
+
+	mx       sync.Mutex
+	token    *token
+	gen      int
+	loaded   bool
+	inflight *tokenCall
 }
 
 type Credential struct {
@@ -40,30 +71,181 @@ type Credential struct {
 	Password     string
 	ClientID     string
 	ClientSecret string
+
+	// RefreshToken seeds the provider with a previously issued refresh
+	// token. When set, the password grant is skipped entirely.
+	RefreshToken string
 }
 
+// Token returns a valid access token, logging in or refreshing it first if
+// needed. Concurrent callers share a single in-flight token request, which
+// runs detached from their contexts so that a caller giving up does not fail
+// the others; ctx only bounds how long this caller waits for it. The request
+// is canceled when the last caller waiting for it gives up, so that the next
+// call starts over.
 func (p *OauthTokenProvider) Token(ctx context.Context) (string, error) {
 	p.mx.Lock()
+
+	if p.token != nil && !p.token.expired(p.now()) {
+		accessToken := p.token.accessToken
+		p.mx.Unlock()
+		return accessToken, nil
+	}
+
+	call := p.inflight
+	if call == nil {
+		callCtx, cancel := context.WithTimeout(context.Background(), p.timeout())
+		call = &tokenCall{done: make(chan struct{}), cancel: cancel}
+		p.inflight = call
+		go p.acquire(callCtx, call)
+	}
+
+	call.waiters++
+
+	p.mx.Unlock()
+
+	select {
+	case <-call.done:
+		return call.accessToken, call.err
+	case <-ctx.Done():
+		p.leave(call)
+		return "", ctx.Err()
+	}
+}
+
+// tokenCall is a token request shared by concurrent callers of Token.
+// waiters is guarded by OauthTokenProvider.mx.
+type tokenCall struct {
+	done        chan struct{}
+	cancel      context.CancelFunc
+	waiters     int
+	accessToken string
+	err         error
+}
+
+func (p *OauthTokenProvider) acquire(ctx context.Context, call *tokenCall) {
+	defer call.cancel()
+
+	call.accessToken, call.err = p.acquireToken(ctx)
+
+	p.mx.Lock()
+	if p.inflight == call {
+		p.inflight = nil
+	}
+	p.mx.Unlock()
+
+	close(call.done)
+}
+
+// leave is called by a caller of Token that gave up waiting for call. The
+// last caller to leave cancels the request.
+func (p *OauthTokenProvider) leave(call *tokenCall) {
+	p.mx.Lock()
 	defer p.mx.Unlock()
 
-	if p.token == nil {
-		if err := p.authorize(ctx); err != nil {
-			return "", err
-		}
-		return p.token.accessToken, nil
+	call.waiters--
+	if call.waiters > 0 {
+		return
 	}
 
-	if p.token.expired(p.now()) {
-		if err := p.refresh(ctx); err != nil {
-			return "", err
+	call.cancel()
+	if p.inflight == call {
+		p.inflight = nil
+	}
+}
+
+func (p *OauthTokenProvider) acquireToken(ctx context.Context) (string, error) {
+	p.mx.Lock()
+	current, gen, loaded := p.token, p.gen, p.loaded
+	p.mx.Unlock()
+
+	if current == nil && p.Store != nil && !loaded {
+		t, err := p.load(ctx)
+		if err != nil && p.OnLoadError != nil {
+			p.OnLoadError(err)
+		}
+
+		p.mx.Lock()
+		p.loaded = true
+		if p.gen == gen {
+			p.token = t
 		}
-		return p.token.accessToken, nil
+		current, gen = p.token, p.gen
+		p.mx.Unlock()
+	}
+
+	if current != nil && !current.expired(p.now()) {
+		return current.accessToken, nil
 	}
 
-	return p.token.accessToken, nil
+	var (
+		t   *token
+		err error
+	)
+
+	switch {
+	case current != nil:
+		t, err = p.refresh(ctx, current.refreshToken)
+	case p.Credential.RefreshToken != "":
+		t, err = p.refresh(ctx, p.Credential.RefreshToken)
+	default:
+		t, err = p.authorize(ctx)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	// A token obtained through LoginWithCode while this request was in
+	// flight takes precedence.
+	p.mx.Lock()
+	superseded := p.gen != gen
+	if !superseded {
+		p.token = t
+		p.gen++
+	}
+	p.mx.Unlock()
+
+	if superseded {
+		return t.accessToken, nil
+	}
+
+	if err := p.save(ctx, t); err != nil && p.OnSaveError != nil {
+		p.OnSaveError(err)
+	}
+
+	return t.accessToken, nil
+}
+
+// InvalidateToken marks accessToken as expired so that the next call to
+// Token refreshes it. It does nothing if the provider has already moved on
+// to another token.
+func (p *OauthTokenProvider) InvalidateToken(accessToken string) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	// Tokens are read outside p.mx once acquired, so the token is replaced
+	// rather than modified in place.
+	if p.token != nil && p.token.accessToken == accessToken {
+		t := *p.token
+		t.expiresIn = 0
+		p.token = &t
+	}
 }
 
-func (p *OauthTokenProvider) authorize(ctx context.Context) error {
+// RefreshToken returns the refresh token currently held by the provider,
+// or an empty string if no token has been issued yet.
+func (p *OauthTokenProvider) RefreshToken() string {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	if p.token == nil {
+		return ""
+	}
+	return p.token.refreshToken
+}
+
+func (p *OauthTokenProvider) authorize(ctx context.Context) (*token, error) {
 	v := url.Values{}
 	v.Set("username", p.Credential.Username)
 	v.Set("password", p.Credential.Password)
@@ -72,52 +254,72 @@ func (p *OauthTokenProvider) authorize(ctx context.Context) error {
 	v.Set("grant_type", "password")
 	v.Set("get_secure_url", "true")
 
+	return p.grant(ctx, v)
+}
+
+func (p *OauthTokenProvider) refresh(ctx context.Context, refreshToken string) (*token, error) {
+	v := url.Values{}
+	v.Set("refresh_token", refreshToken)
+	v.Set("client_id", p.Credential.ClientID)
+	v.Set("client_secret", p.Credential.ClientSecret)
+	v.Set("grant_type", "refresh_token")
+	v.Set("get_secure_url", "true")
+
+	return p.grant(ctx, v)
+}
+
+// grant requests a token from /auth/token. It does not touch the token held
+// by the provider, so it is called without holding p.mx.
+func (p *OauthTokenProvider) grant(ctx context.Context, v url.Values) (*token, error) {
 	req, err := http.NewRequest(http.MethodPost, p.baseURL()+"/auth/token", strings.NewReader(v.Encode()))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	res, err := p.request(req.WithContext(ctx))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer res.Body.Close()
 
 	if !(200 <= res.StatusCode && res.StatusCode <= 299) {
-		return p.onFailure(res)
+		return nil, p.onFailure(res)
 	}
 
 	return p.onSuccess(res)
 }
 
-func (p *OauthTokenProvider) refresh(ctx context.Context) error {
-	v := url.Values{}
-	v.Set("refresh_token", p.token.refreshToken)
-	v.Set("client_id", p.Credential.ClientID)
-	v.Set("client_secret", p.Credential.ClientSecret)
-	v.Set("grant_type", "refresh_token")
-	v.Set("get_secure_url", "true")
-
-	req, err := http.NewRequest(http.MethodPost, p.baseURL()+"/auth/token", strings.NewReader(v.Encode()))
+func (p *OauthTokenProvider) load(ctx context.Context) (*token, error) {
+	t, err := p.Store.Load(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-
-	res, err := p.request(req.WithContext(ctx))
-	if err != nil {
-		return err
+	if t == nil {
+		return nil, nil
 	}
-	defer res.Body.Close()
 
-	if !(200 <= res.StatusCode && res.StatusCode <= 299) {
-		return p.onFailure(res)
+	return &token{
+		accessToken:  t.AccessToken,
+		refreshToken: t.RefreshToken,
+		createdAt:    t.CreatedAt,
+		expiresIn:    time.Duration(t.ExpiresIn) * time.Second,
+	}, nil
+}
+
+func (p *OauthTokenProvider) save(ctx context.Context, t *token) error {
+	if p.Store == nil {
+		return nil
 	}
 
-	return p.onSuccess(res)
+	return p.Store.Save(ctx, &StoredToken{
+		AccessToken:  t.accessToken,
+		RefreshToken: t.refreshToken,
+		CreatedAt:    t.createdAt,
+		ExpiresIn:    int(t.expiresIn / time.Second),
+	})
 }
 
 func (p *OauthTokenProvider) request(req *http.Request) (*http.Response, error) {
@@ -128,25 +330,23 @@ func (p *OauthTokenProvider) request(req *http.Request) (*http.Response, error)
 	return p.client().Do(req)
 }
 
-func (p *OauthTokenProvider) onSuccess(res *http.Response) error {
+func (p *OauthTokenProvider) onSuccess(res *http.Response) (*token, error) {
 	if !strings.Contains(res.Header.Get("Content-Type"), "application/json") {
-		return fmt.Errorf("Content-Type header = %q, should be \"application/json\"", res.Header.Get("Content-Type"))
+		return nil, fmt.Errorf("Content-Type header = %q, should be \"application/json\"", res.Header.Get("Content-Type"))
 	}
 
 	var t Token
 
 	if err := json.NewDecoder(res.Body).Decode(&t); err != nil {
-		return err
+		return nil, err
 	}
 
-	p.token = &token{
+	return &token{
 		accessToken:  t.Response.AccessToken,
 		refreshToken: t.Response.RefreshToken,
 		createdAt:    p.now(),
 		expiresIn:    time.Duration(t.Response.ExpiresIn) * time.Second,
-	}
-
-	return nil
+	}, nil
 }
 
 func (p *OauthTokenProvider) onFailure(res *http.Response) error {
@@ -164,6 +364,13 @@ func (p *OauthTokenProvider) onFailure(res *http.Response) error {
 	return errToken
 }
 
+func (p *OauthTokenProvider) timeout() time.Duration {
+	if p.Timeout == 0 {
+		return DefaultTokenTimeout
+	}
+	return p.Timeout
+}
+
 func (p *OauthTokenProvider) client() *http.Client {
 	if p.Client == nil {
 		return http.DefaultClient
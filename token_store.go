@@ -0,0 +1,121 @@
+package pixiv
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// TokenStore persists tokens issued to an OauthTokenProvider across
+// process restarts.
+type TokenStore interface {
+	// Load returns the stored token, or nil if nothing has been stored yet.
+	Load(ctx context.Context) (*StoredToken, error)
+	Save(ctx context.Context, token *StoredToken) error
+}
+
+type StoredToken struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresIn    int       `json:"expires_in"`
+}
+
+// FileTokenStore stores a token as JSON in the file at Path. The file is
+// replaced atomically and is readable only by its owner.
+type FileTokenStore struct {
+	Path string
+}
+
+func (s *FileTokenStore) Load(_ context.Context) (*StoredToken, error) {
+	buf, err := ioutil.ReadFile(s.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var t StoredToken
+
+	if err := json.Unmarshal(buf, &t); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (s *FileTokenStore) Save(_ context.Context, token *StoredToken) error {
+	buf, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(s.Path), "."+filepath.Base(s.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	tmp := f.Name()
+
+	if err := writeTokenFile(f, buf); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, s.Path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return nil
+}
+
+func writeTokenFile(f *os.File, buf []byte) error {
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return err
+	}
+
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+type MemoryTokenStore struct {
+	mx    sync.Mutex
+	token *StoredToken
+}
+
+func (s *MemoryTokenStore) Load(_ context.Context) (*StoredToken, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.token == nil {
+		return nil, nil
+	}
+
+	t := *s.token
+	return &t, nil
+}
+
+func (s *MemoryTokenStore) Save(_ context.Context, token *StoredToken) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	t := *token
+	s.token = &t
+	return nil
+}
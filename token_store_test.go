@@ -0,0 +1,317 @@
+package pixiv
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFileTokenStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-pixiv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := &FileTokenStore{Path: filepath.Join(dir, "token.json")}
+
+	loaded, err := store.Load(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded != nil {
+		t.Errorf("Load() should return nil if no token is stored, got %#v", loaded)
+	}
+
+	saved := &StoredToken{
+		AccessToken:  "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY",
+		RefreshToken: "wgNv1gZ0y8Z1nIyG4bRbpT2yNMs3hvHhHLIhXDc47G8",
+		CreatedAt:    time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresIn:    3600,
+	}
+
+	if err := store.Save(context.TODO(), saved); err != nil {
+		t.Fatal(err)
+	}
+
+	fi, err := os.Stat(store.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := fi.Mode().Perm(), os.FileMode(0600); g != e {
+		t.Errorf("got file mode %v, want %v", g, e)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := len(files), 1; g != e {
+		t.Errorf("got %d files in the directory, want %d", g, e)
+	}
+
+	loaded, err = store.Load(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := loaded, saved; !reflect.DeepEqual(g, e) {
+		t.Errorf("got %#v, want %#v", g, e)
+	}
+}
+
+func TestMemoryTokenStore(t *testing.T) {
+	store := &MemoryTokenStore{}
+
+	loaded, err := store.Load(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded != nil {
+		t.Errorf("Load() should return nil if no token is stored, got %#v", loaded)
+	}
+
+	saved := &StoredToken{AccessToken: "ACCESS_TOKEN", RefreshToken: "REFRESH_TOKEN", ExpiresIn: 3600}
+
+	if err := store.Save(context.TODO(), saved); err != nil {
+		t.Fatal(err)
+	}
+
+	saved.AccessToken = "MODIFIED"
+
+	loaded, err = store.Load(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := loaded.AccessToken, "ACCESS_TOKEN"; g != e {
+		t.Errorf("got AccessToken %q, want %q", g, e)
+	}
+}
+
+func TestOauthTokenProvider_Token_Store(t *testing.T) {
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			cnt++
+		}()
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		switch cnt {
+		case 0:
+			if g, e := r.Form.Get("grant_type"), "refresh_token"; g != e {
+				t.Errorf("got grant_type %q, want %q", g, e)
+			}
+
+			if g, e := r.Form.Get("refresh_token"), "STORED_REFRESH_TOKEN"; g != e {
+				t.Errorf("got refresh_token %q, want %q", g, e)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(fixture("fixtures/token_refresh.json"))
+		default:
+			t.Fatal("too many requests")
+		}
+	}))
+	defer ts.Close()
+
+	store := &MemoryTokenStore{}
+	store.Save(context.TODO(), &StoredToken{
+		AccessToken:  "STORED_ACCESS_TOKEN",
+		RefreshToken: "STORED_REFRESH_TOKEN",
+		CreatedAt:    time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresIn:    3600,
+	})
+
+	var now time.Time
+
+	tp := &OauthTokenProvider{
+		BaseURL: ts.URL,
+		Credential: Credential{
+			Username:     "USERNAME",
+			Password:     "PASSWORD",
+			ClientID:     "CLIENT_ID",
+			ClientSecret: "CLIENT_SECRET",
+		},
+		Now: func() time.Time {
+			return now
+		},
+		Store: store,
+	}
+
+	now = time.Date(2017, 1, 1, 0, 30, 0, 0, time.UTC)
+
+	token1, err := tp.Token(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := token1, "STORED_ACCESS_TOKEN"; g != e {
+		t.Errorf("got token %q, want %q", g, e)
+	}
+
+	now = time.Date(2017, 1, 1, 1, 0, 0, 0, time.UTC)
+
+	token2, err := tp.Token(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := token2, "cIPvPp368gKDU4DP7sXhbFzqKiXrGpwFJrbXF40fpUY"; g != e {
+		t.Errorf("got token %q, want %q", g, e)
+	}
+
+	stored, err := store.Load(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedStored := &StoredToken{
+		AccessToken:  "cIPvPp368gKDU4DP7sXhbFzqKiXrGpwFJrbXF40fpUY",
+		RefreshToken: "wgNv1gZ0y8Z1nIyG4bRbpT2yNMs3hvHhHLIhXDc47G8",
+		CreatedAt:    time.Date(2017, 1, 1, 1, 0, 0, 0, time.UTC),
+		ExpiresIn:    3600,
+	}
+	if g, e := stored, expectedStored; !reflect.DeepEqual(g, e) {
+		t.Errorf("got stored token %#v, want %#v", g, e)
+	}
+}
+
+type failingTokenStore struct {
+	MemoryTokenStore
+	err error
+}
+
+func (s *failingTokenStore) Save(ctx context.Context, token *StoredToken) error {
+	return s.err
+}
+
+func TestOauthTokenProvider_Token_SaveError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture("fixtures/token_authorize.json"))
+	}))
+	defer ts.Close()
+
+	store := &failingTokenStore{err: errors.New("disk full")}
+
+	var saveErrs []error
+
+	tp := &OauthTokenProvider{
+		BaseURL: ts.URL,
+		Credential: Credential{
+			Username:     "USERNAME",
+			Password:     "PASSWORD",
+			ClientID:     "CLIENT_ID",
+			ClientSecret: "CLIENT_SECRET",
+		},
+		Store: store,
+		OnSaveError: func(err error) {
+			saveErrs = append(saveErrs, err)
+		},
+	}
+
+	token, err := tp.Token(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := token, "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"; g != e {
+		t.Errorf("got token %q, want %q", g, e)
+	}
+
+	if g, e := saveErrs, []error{store.err}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got save errors %v, want %v", g, e)
+	}
+}
+
+func TestOauthTokenProvider_Token_LoadError(t *testing.T) {
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			cnt++
+		}()
+
+		if cnt > 0 {
+			t.Fatal("too many requests")
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		if g, e := r.Form.Get("grant_type"), "password"; g != e {
+			t.Errorf("got grant_type %q, want %q", g, e)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture("fixtures/token_authorize.json"))
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "go-pixiv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "token.json")
+	if err := ioutil.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var loadErrs []error
+
+	tp := &OauthTokenProvider{
+		BaseURL: ts.URL,
+		Credential: Credential{
+			Username:     "USERNAME",
+			Password:     "PASSWORD",
+			ClientID:     "CLIENT_ID",
+			ClientSecret: "CLIENT_SECRET",
+		},
+		Store: &FileTokenStore{Path: path},
+		OnLoadError: func(err error) {
+			loadErrs = append(loadErrs, err)
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		token, err := tp.Token(context.TODO())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if g, e := token, "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"; g != e {
+			t.Errorf("got token %q, want %q", g, e)
+		}
+	}
+
+	if g, e := len(loadErrs), 1; g != e {
+		t.Errorf("got %d load errors, want %d", g, e)
+	}
+
+	// The empty file is replaced by the new token.
+	stored, err := tp.Store.Load(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := stored.AccessToken, "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"; g != e {
+		t.Errorf("got stored access token %q, want %q", g, e)
+	}
+}
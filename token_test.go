@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 )
@@ -125,6 +126,65 @@ func TestOauthTokenProvider_Token(t *testing.T) {
 	}
 }
 
+func TestOauthTokenProvider_Token_RefreshToken(t *testing.T) {
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			cnt++
+		}()
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		switch cnt {
+		case 0:
+			expectedForm := url.Values{
+				"refresh_token":  []string{"SEEDED_REFRESH_TOKEN"},
+				"client_id":      []string{"CLIENT_ID"},
+				"client_secret":  []string{"CLIENT_SECRET"},
+				"grant_type":     []string{"refresh_token"},
+				"get_secure_url": []string{"true"},
+			}
+			if g, e := r.Form, expectedForm; !reflect.DeepEqual(g, e) {
+				t.Errorf("got form %#v, want %#v", g, e)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(fixture("fixtures/token_refresh.json"))
+		default:
+			t.Fatal("too many requests")
+		}
+	}))
+	defer ts.Close()
+
+	tp := &OauthTokenProvider{
+		BaseURL: ts.URL,
+		Credential: Credential{
+			ClientID:     "CLIENT_ID",
+			ClientSecret: "CLIENT_SECRET",
+			RefreshToken: "SEEDED_REFRESH_TOKEN",
+		},
+	}
+
+	if g, e := tp.RefreshToken(), ""; g != e {
+		t.Errorf("got refresh token %q, want %q", g, e)
+	}
+
+	token, err := tp.Token(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := token, "cIPvPp368gKDU4DP7sXhbFzqKiXrGpwFJrbXF40fpUY"; g != e {
+		t.Errorf("got token %q, want %q", g, e)
+	}
+
+	if g, e := tp.RefreshToken(), "wgNv1gZ0y8Z1nIyG4bRbpT2yNMs3hvHhHLIhXDc47G8"; g != e {
+		t.Errorf("got refresh token %q, want %q", g, e)
+	}
+}
+
 func TestOauthTokenProvider_Token_BadRequest(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -204,3 +264,338 @@ func TestToken_Expired(t *testing.T) {
 		})
 	}
 }
+
+func TestOauthTokenProvider_InvalidateToken(t *testing.T) {
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			cnt++
+		}()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch cnt {
+		case 0:
+			w.Write(fixture("fixtures/token_authorize.json"))
+		case 1:
+			w.Write(fixture("fixtures/token_refresh.json"))
+		default:
+			t.Fatal("too many requests")
+		}
+	}))
+	defer ts.Close()
+
+	now := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tp := &OauthTokenProvider{
+		BaseURL: ts.URL,
+		Credential: Credential{
+			Username:     "USERNAME",
+			Password:     "PASSWORD",
+			ClientID:     "CLIENT_ID",
+			ClientSecret: "CLIENT_SECRET",
+		},
+		Now: func() time.Time {
+			return now
+		},
+	}
+
+	token1, err := tp.Token(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tp.InvalidateToken(token1)
+
+	token2, err := tp.Token(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := token2, "cIPvPp368gKDU4DP7sXhbFzqKiXrGpwFJrbXF40fpUY"; g != e {
+		t.Errorf("got token %q, want %q", g, e)
+	}
+
+	// A stale token must not invalidate the current one.
+	tp.InvalidateToken(token1)
+
+	token3, err := tp.Token(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := token3, token2; g != e {
+		t.Errorf("got token %q, want %q", g, e)
+	}
+}
+
+func TestOauthTokenProvider_Token_Concurrent(t *testing.T) {
+	var (
+		mx  sync.Mutex
+		cnt int
+	)
+
+	arrived := make(chan struct{})
+	release := make(chan struct{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mx.Lock()
+		cnt++
+		mx.Unlock()
+
+		close(arrived)
+		<-release
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture("fixtures/token_authorize.json"))
+	}))
+	defer ts.Close()
+
+	tp := &OauthTokenProvider{
+		BaseURL: ts.URL,
+		Credential: Credential{
+			Username:     "USERNAME",
+			Password:     "PASSWORD",
+			ClientID:     "CLIENT_ID",
+			ClientSecret: "CLIENT_SECRET",
+		},
+	}
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 10)
+	errs := make([]error, 10)
+
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], errs[i] = tp.Token(context.TODO())
+		}(i)
+	}
+
+	<-arrived
+
+	// A waiter giving up does not wait for the in-flight request, nor does
+	// it fail it for the others.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := tp.Token(ctx); err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+
+	close(release)
+	wg.Wait()
+
+	for i := range tokens {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+
+		if g, e := tokens[i], "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"; g != e {
+			t.Errorf("got token %q, want %q", g, e)
+		}
+	}
+
+	if g, e := cnt, 1; g != e {
+		t.Errorf("got %d token requests, want %d", g, e)
+	}
+}
+
+func TestOauthTokenProvider_Token_RetryAfterFailure(t *testing.T) {
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			cnt++
+		}()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch cnt {
+		case 0:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(fixture("fixtures/token_error.json"))
+		case 1:
+			w.Write(fixture("fixtures/token_authorize.json"))
+		default:
+			t.Fatal("too many requests")
+		}
+	}))
+	defer ts.Close()
+
+	tp := &OauthTokenProvider{
+		BaseURL: ts.URL,
+		Credential: Credential{
+			Username:     "USERNAME",
+			Password:     "PASSWORD",
+			ClientID:     "CLIENT_ID",
+			ClientSecret: "CLIENT_SECRET",
+		},
+	}
+
+	if _, err := tp.Token(context.TODO()); err == nil {
+		t.Fatal("Token() should return an error if 400 is received")
+	}
+
+	token, err := tp.Token(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := token, "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"; g != e {
+		t.Errorf("got token %q, want %q", g, e)
+	}
+}
+
+func TestOauthTokenProvider_Token_Timeout(t *testing.T) {
+	done := make(chan struct{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	tp := &OauthTokenProvider{
+		BaseURL: ts.URL,
+		Credential: Credential{
+			Username:     "USERNAME",
+			Password:     "PASSWORD",
+			ClientID:     "CLIENT_ID",
+			ClientSecret: "CLIENT_SECRET",
+		},
+		Timeout: 10 * time.Millisecond,
+	}
+
+	if _, err := tp.Token(context.Background()); err == nil {
+		t.Fatal("Token() should return an error if the token request times out")
+	}
+}
+
+func TestOauthTokenProvider_Token_HungRequest(t *testing.T) {
+	done := make(chan struct{})
+
+	var mx sync.Mutex
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mx.Lock()
+		n := cnt
+		cnt++
+		mx.Unlock()
+
+		if n == 0 {
+			select {
+			case <-r.Context().Done():
+			case <-done:
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture("fixtures/token_authorize.json"))
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	tp := &OauthTokenProvider{
+		BaseURL: ts.URL,
+		Credential: Credential{
+			Username:     "USERNAME",
+			Password:     "PASSWORD",
+			ClientID:     "CLIENT_ID",
+			ClientSecret: "CLIENT_SECRET",
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := tp.Token(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// The hung request was abandoned by its only caller, so the next call
+	// starts a new one instead of waiting for it.
+	token, err := tp.Token(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := token, "ATN7bmWC7Kg1OneEqSPa9GxKm1l1uVHa8cQQKme7BGY"; g != e {
+		t.Errorf("got token %q, want %q", g, e)
+	}
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	if g, e := cnt, 2; g != e {
+		t.Errorf("got %d token requests, want %d", g, e)
+	}
+}
+
+func TestOauthTokenProvider_Token_WaiterLeaves(t *testing.T) {
+	release := make(chan struct{})
+
+	var mx sync.Mutex
+	cnt := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mx.Lock()
+		cnt++
+		mx.Unlock()
+
+		<-release
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture("fixtures/token_authorize.json"))
+	}))
+	defer ts.Close()
+
+	tp := &OauthTokenProvider{
+		BaseURL: ts.URL,
+		Credential: Credential{
+			Username:     "USERNAME",
+			Password:     "PASSWORD",
+			ClientID:     "CLIENT_ID",
+			ClientSecret: "CLIENT_SECRET",
+		},
+	}
+
+	result := make(chan error)
+	go func() {
+		_, err := tp.Token(context.Background())
+		result <- err
+	}()
+
+	// Wait for the first caller to start the request.
+	for {
+		mx.Lock()
+		n := cnt
+		mx.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := tp.Token(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// The request is still awaited by the first caller, so it is not
+	// canceled.
+	close(release)
+
+	if err := <-result; err != nil {
+		t.Fatal(err)
+	}
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	if g, e := cnt, 1; g != e {
+		t.Errorf("got %d token requests, want %d", g, e)
+	}
+}
@@ -0,0 +1,124 @@
+package pixiv
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// DownloadUgoiraGIF downloads the frame zip of an ugoira and writes it to w
+// as an endlessly looping animated GIF. If cli is nil, http.DefaultClient is
+// used.
+func DownloadUgoiraGIF(ctx context.Context, cli *http.Client, metadata *GetUgoiraMetadataUgoiraMetadata, w io.Writer) error {
+	zipURL := ugoiraZipURL(metadata.ZipURLs)
+	if zipURL == "" {
+		return fmt.Errorf("ugoira metadata has no zip URL")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, zipURL, nil)
+	if err != nil {
+		return err
+	}
+
+	SetDownloadHeaders(req)
+
+	if cli == nil {
+		cli = http.DefaultClient
+	}
+
+	res, err := cli.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ErrDownload{URL: zipURL, StatusCode: res.StatusCode, Status: res.Status}
+	}
+
+	buf, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	return EncodeUgoiraGIF(w, bytes.NewReader(buf), int64(len(buf)), metadata.Frames)
+}
+
+// EncodeUgoiraGIF assembles the frames contained in an ugoira zip into an
+// animated GIF, using the per-frame delays given in milliseconds.
+func EncodeUgoiraGIF(w io.Writer, r io.ReaderAt, size int64, frames []GetUgoiraMetadataUgoiraMetadataFrame) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	anim := &gif.GIF{}
+
+	for _, frame := range frames {
+		f, ok := files[frame.File]
+		if !ok {
+			return fmt.Errorf("ugoira zip has no frame %q", frame.File)
+		}
+
+		img, err := decodeZipImage(f)
+		if err != nil {
+			return err
+		}
+
+		paletted := image.NewPaletted(img.Bounds(), palette.Plan9)
+		draw.FloydSteinberg.Draw(paletted, img.Bounds(), img, img.Bounds().Min)
+
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, (frame.Delay+5)/10)
+	}
+
+	if len(anim.Image) == 0 {
+		return fmt.Errorf("ugoira has no frames")
+	}
+
+	return gif.EncodeAll(w, anim)
+}
+
+func decodeZipImage(f *zip.File) (image.Image, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	img, _, err := image.Decode(rc)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %v", f.Name, err)
+	}
+
+	return img, nil
+}
+
+func ugoiraZipURL(zipURLs map[string]string) string {
+	for _, size := range []string{"original", "large", "medium"} {
+		if u, ok := zipURLs[size]; ok {
+			return u
+		}
+	}
+
+	for _, u := range zipURLs {
+		return u
+	}
+
+	return ""
+}
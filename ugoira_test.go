@@ -0,0 +1,142 @@
+package pixiv
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func ugoiraZip(t *testing.T, colors map[string]color.Color) []byte {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+
+	for name, c := range colors {
+		img := image.NewRGBA(image.Rect(0, 0, 4, 3))
+		for y := 0; y < 3; y++ {
+			for x := 0; x < 4; x++ {
+				img.Set(x, y, c)
+			}
+		}
+
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := png.Encode(w, img); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestEncodeUgoiraGIF(t *testing.T) {
+	data := ugoiraZip(t, map[string]color.Color{
+		"000000.png": color.RGBA{0xff, 0x00, 0x00, 0xff},
+		"000001.png": color.RGBA{0x00, 0x00, 0xff, 0xff},
+	})
+
+	frames := []GetUgoiraMetadataUgoiraMetadataFrame{
+		{File: "000000.png", Delay: 70},
+		{File: "000001.png", Delay: 250},
+		{File: "000000.png", Delay: 100},
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := EncodeUgoiraGIF(buf, bytes.NewReader(data), int64(len(data)), frames); err != nil {
+		t.Fatal(err)
+	}
+
+	anim, err := gif.DecodeAll(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := anim.Delay, []int{7, 25, 10}; !reflect.DeepEqual(g, e) {
+		t.Errorf("got delays %v, want %v", g, e)
+	}
+
+	if g, e := anim.Image[0].Bounds(), image.Rect(0, 0, 4, 3); g != e {
+		t.Errorf("got bounds %v, want %v", g, e)
+	}
+
+	if r, _, b, _ := anim.Image[1].At(0, 0).RGBA(); r != 0 || b != 0xffff {
+		t.Errorf("got frame 1 color %v, want blue", anim.Image[1].At(0, 0))
+	}
+}
+
+func TestEncodeUgoiraGIF_MissingFrame(t *testing.T) {
+	data := ugoiraZip(t, map[string]color.Color{
+		"000000.png": color.Black,
+	})
+
+	frames := []GetUgoiraMetadataUgoiraMetadataFrame{
+		{File: "000001.png", Delay: 70},
+	}
+
+	if err := EncodeUgoiraGIF(&bytes.Buffer{}, bytes.NewReader(data), int64(len(data)), frames); err == nil {
+		t.Errorf("EncodeUgoiraGIF() should return an error if a frame is missing from the zip")
+	}
+}
+
+func TestDownloadUgoiraGIF(t *testing.T) {
+	data := ugoiraZip(t, map[string]color.Color{
+		"000000.png": color.White,
+		"000001.png": color.Black,
+	})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for k, v := range DefaultDownloadHeaders {
+			if r.Header.Get(k) != v {
+				t.Errorf("got %s header = %q, want %q", k, r.Header.Get(k), v)
+			}
+		}
+
+		if g, e := r.URL.Path, "/img-zip-ugoira/img/64885306_ugoira600x600.zip"; g != e {
+			t.Errorf("got URL path %q, want %q", g, e)
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Write(data)
+	}))
+	defer ts.Close()
+
+	metadata := &GetUgoiraMetadataUgoiraMetadata{
+		ZipURLs: map[string]string{
+			"medium": ts.URL + "/img-zip-ugoira/img/64885306_ugoira600x600.zip",
+		},
+		Frames: []GetUgoiraMetadataUgoiraMetadataFrame{
+			{File: "000000.png", Delay: 40},
+			{File: "000001.png", Delay: 40},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := DownloadUgoiraGIF(context.TODO(), nil, metadata, buf); err != nil {
+		t.Fatal(err)
+	}
+
+	anim, err := gif.DecodeAll(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := len(anim.Image), 2; g != e {
+		t.Errorf("got %d frames, want %d", g, e)
+	}
+}